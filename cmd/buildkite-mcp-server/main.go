@@ -5,9 +5,10 @@ import (
 	"os"
 
 	"github.com/alecthomas/kong"
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite"
 	"github.com/buildkite/buildkite-mcp-server/internal/commands"
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
-	"github.com/buildkite/go-buildkite/v4"
+	gobuildkite "github.com/buildkite/go-buildkite/v4"
 	"github.com/rs/zerolog"
 )
 
@@ -15,13 +16,14 @@ var (
 	version = "dev"
 
 	cli struct {
-		Stdio       commands.StdioCmd `cmd:"" help:"stdio mcp server."`
-		HTTP        commands.HTTPCmd  `cmd:"" help:"http mcp server."`
-		APIToken    string            `help:"The Buildkite API token to use." env:"BUILDKITE_API_TOKEN"`
-		BaseURL     string            `help:"The base URL of the Buildkite API to use." env:"BUILDKITE_BASE_URL" default:"https://api.buildkite.com/"`
-		Debug       bool              `help:"Enable debug mode."`
-		HTTPHeaders []string          `help:"Additional HTTP headers to send with every request. Format: 'Key: Value'" name:"http-header" env:"BUILDKITE_HTTP_HEADERS"`
-		Version     kong.VersionFlag
+		Stdio          commands.StdioCmd `cmd:"" help:"stdio mcp server."`
+		HTTP           commands.HTTPCmd  `cmd:"" help:"http mcp server."`
+		APIToken       string            `help:"The Buildkite API token to use." env:"BUILDKITE_API_TOKEN"`
+		BaseURL        string            `help:"The base URL of the Buildkite API to use." env:"BUILDKITE_BASE_URL" default:"https://api.buildkite.com/"`
+		Debug          bool              `help:"Enable debug mode."`
+		HTTPHeaders    []string          `help:"Additional HTTP headers to send with every request. Format: 'Key: Value'" name:"http-header" env:"BUILDKITE_HTTP_HEADERS"`
+		SkipScopeCheck bool              `help:"Enable every tool without checking the API token has the scopes they need." env:"BUILDKITE_SKIP_SCOPE_CHECK"`
+		Version        kong.VersionFlag
 	}
 )
 
@@ -55,16 +57,20 @@ func main() {
 	// Parse additional headers into a map
 	headers := commands.ParseHeaders(cli.HTTPHeaders, logger)
 
-	client, err := buildkite.NewOpts(
-		buildkite.WithTokenAuth(cli.APIToken),
-		buildkite.WithUserAgent(commands.UserAgent(version)),
-		buildkite.WithHTTPClient(trace.NewHTTPClientWithHeaders(headers)),
-		buildkite.WithBaseURL(cli.BaseURL),
+	// report rate limits to the caller rather than letting go-buildkite retry them
+	httpClient := trace.NewHTTPClientWithHeaders(headers)
+	httpClient.Transport = &buildkite.RateLimitTransport{Wrapped: httpClient.Transport}
+
+	client, err := gobuildkite.NewOpts(
+		gobuildkite.WithTokenAuth(cli.APIToken),
+		gobuildkite.WithUserAgent(commands.UserAgent(version)),
+		gobuildkite.WithHTTPClient(httpClient),
+		gobuildkite.WithBaseURL(cli.BaseURL),
 	)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create buildkite client")
 	}
 
-	err = cmd.Run(&commands.Globals{Version: version, Client: client, Logger: logger})
+	err = cmd.Run(&commands.Globals{Version: version, Client: client, Logger: logger, SkipScopeCheck: cli.SkipScopeCheck})
 	cmd.FatalIfErrorf(err)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -28,11 +29,11 @@ func AccessToken(ctx context.Context, client AccessTokenClient) (tool mcp.Tool,
 
 			token, resp, err := client.Get(ctx)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get access token", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("failed to get access token"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get access token", resp, nil), nil
 			}
 
 			r, err := json.Marshal(&token)
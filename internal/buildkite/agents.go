@@ -0,0 +1,248 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AgentsClient interface {
+	List(ctx context.Context, org string, opts *buildkite.AgentListOptions) ([]buildkite.Agent, *buildkite.Response, error)
+	Get(ctx context.Context, org, id string) (buildkite.Agent, *buildkite.Response, error)
+	Stop(ctx context.Context, org, id string, force bool) (*buildkite.Response, error)
+}
+
+func ListAgents(ctx context.Context, client AgentsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_agents",
+			mcp.WithDescription("List agents connected to an organization with their name, hostname, version, connection state, tags and current job. Filter by name, hostname, version or queue tag"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the agents"),
+			),
+			mcp.WithString("name",
+				mcp.Description("Filter agents by name"),
+			),
+			mcp.WithString("hostname",
+				mcp.Description("Filter agents by hostname"),
+			),
+			mcp.WithString("version",
+				mcp.Description("Filter agents by exact agent version"),
+			),
+			mcp.WithString("queue",
+				mcp.Description("Filter agents by their queue tag, for example 'default' matches agents tagged 'queue=default'. Applied to the returned page of agents"),
+			),
+			withPagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "List Agents",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ListAgents")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			name := request.GetString("name", "")
+			hostname := request.GetString("hostname", "")
+			version := request.GetString("version", "")
+			queue := request.GetString("queue", "")
+
+			paginationParams, err := optionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("name", name),
+				attribute.String("hostname", hostname),
+				attribute.String("version", version),
+				attribute.String("queue", queue),
+				attribute.Int("page", paginationParams.Page),
+				attribute.Int("per_page", paginationParams.PerPage),
+			)
+
+			agents, resp, err := client.List(ctx, org, &buildkite.AgentListOptions{
+				Name:        name,
+				Hostname:    hostname,
+				Version:     version,
+				ListOptions: paginationParams,
+			})
+			if err != nil {
+				return apiErrorResult("list agents", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list agents", resp, nil), nil
+			}
+
+			// the API has no queue filter, so match the queue tag on the page we were given
+			filtered := make([]buildkite.Agent, 0, len(agents))
+			for _, agent := range agents {
+				if queue != "" && !agentHasTag(agent, "queue="+queue) {
+					continue
+				}
+				filtered = append(filtered, redactAgent(agent))
+			}
+
+			result := PaginatedResult[buildkite.Agent]{
+				Items: filtered,
+				Headers: map[string]string{
+					"Link": resp.Header.Get("Link"),
+				},
+			}
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal agents: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func GetAgent(ctx context.Context, client AgentsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_agent",
+			mcp.WithDescription("Get detailed information about a specific agent including its hostname, IP address, version, connection state, tags and the job it is currently running. Use the agent ID from a job to find which host ran it"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the agent"),
+			),
+			mcp.WithString("agent_id",
+				mcp.Required(),
+				mcp.Description("The ID of the agent"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get Agent",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetAgent")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			agentID, err := request.RequireString("agent_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("agent_id", agentID),
+			)
+
+			agent, resp, err := client.Get(ctx, org, agentID)
+			if err != nil {
+				return apiErrorResult("get agent", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get agent", resp, nil), nil
+			}
+
+			agent = redactAgent(agent)
+
+			r, err := json.Marshal(&agent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal agent: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func StopAgent(ctx context.Context, client AgentsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("stop_agent",
+			mcp.WithDescription("Stop an agent. By default the agent finishes its current job before stopping; set force to cancel the running job immediately"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the agent"),
+			),
+			mcp.WithString("agent_id",
+				mcp.Required(),
+				mcp.Description("The ID of the agent"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Stop the agent immediately, cancelling any job it is running (default false)"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Stop Agent",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.StopAgent")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			agentID, err := request.RequireString("agent_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			force := request.GetBool("force", false)
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("agent_id", agentID),
+				attribute.Bool("force", force),
+			)
+
+			resp, err := client.Stop(ctx, org, agentID, force)
+			if err != nil {
+				return apiErrorResult("stop agent", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+				return apiErrorResult("stop agent", resp, nil), nil
+			}
+
+			r, err := json.Marshal(map[string]any{
+				"agent_id": agentID,
+				"stopped":  true,
+				"force":    force,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal stop agent response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func agentHasTag(agent buildkite.Agent, tag string) bool {
+	for _, metadata := range agent.Metadata {
+		if metadata == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAgent removes the agent's access token, which should never be sent to the LLM
+func redactAgent(agent buildkite.Agent) buildkite.Agent {
+	agent.AgentToken = ""
+	return agent
+}
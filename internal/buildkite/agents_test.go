@@ -0,0 +1,139 @@
+package buildkite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAgentsClient struct {
+	ListFunc func(ctx context.Context, org string, opts *buildkite.AgentListOptions) ([]buildkite.Agent, *buildkite.Response, error)
+	GetFunc  func(ctx context.Context, org, id string) (buildkite.Agent, *buildkite.Response, error)
+	StopFunc func(ctx context.Context, org, id string, force bool) (*buildkite.Response, error)
+}
+
+func (m *MockAgentsClient) List(ctx context.Context, org string, opts *buildkite.AgentListOptions) ([]buildkite.Agent, *buildkite.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, org, opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockAgentsClient) Get(ctx context.Context, org, id string) (buildkite.Agent, *buildkite.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, org, id)
+	}
+	return buildkite.Agent{}, nil, nil
+}
+
+func (m *MockAgentsClient) Stop(ctx context.Context, org, id string, force bool) (*buildkite.Response, error) {
+	if m.StopFunc != nil {
+		return m.StopFunc(ctx, org, id, force)
+	}
+	return nil, nil
+}
+
+var _ AgentsClient = (*MockAgentsClient)(nil)
+
+func TestListAgents(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockAgentsClient{
+		ListFunc: func(ctx context.Context, org string, opts *buildkite.AgentListOptions) ([]buildkite.Agent, *buildkite.Response, error) {
+			assert.Equal("ci-host-1", opts.Hostname)
+			return []buildkite.Agent{
+					{ID: "agent1", Hostname: "ci-host-1", AgentToken: "secret", Metadata: []string{"queue=default"}},
+					{ID: "agent2", Hostname: "ci-host-1", Metadata: []string{"queue=deploy"}},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := ListAgents(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":      "org",
+		"hostname": "ci-host-1",
+		"queue":    "default",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"headers":{"Link":""},"items":[{"id":"agent1","hostname":"ci-host-1","meta_data":["queue=default"]}]}`, textContent.Text)
+}
+
+func TestGetAgent(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockAgentsClient{
+		GetFunc: func(ctx context.Context, org, id string) (buildkite.Agent, *buildkite.Response, error) {
+			return buildkite.Agent{
+					ID:             id,
+					Hostname:       "ci-host-1",
+					ConnectedState: "connected",
+					AgentToken:     "secret",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := GetAgent(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":      "org",
+		"agent_id": "agent1",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"id":"agent1","connection_state":"connected","hostname":"ci-host-1"}`, textContent.Text)
+}
+
+func TestStopAgent(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockAgentsClient{
+		StopFunc: func(ctx context.Context, org, id string, force bool) (*buildkite.Response, error) {
+			assert.Equal("agent1", id)
+			assert.True(force)
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := StopAgent(ctx, client)
+	assert.NotNil(tool)
+	assert.True(*tool.Annotations.DestructiveHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":      "org",
+		"agent_id": "agent1",
+		"force":    true,
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"agent_id":"agent1","force":true,"stopped":true}`, textContent.Text)
+}
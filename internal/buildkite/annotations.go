@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/markdown"
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -17,12 +17,50 @@ import (
 // AnnotationsClient describes the subset of the Buildkite client we need for annotations.
 type AnnotationsClient interface {
 	ListByBuild(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error)
+	Create(ctx context.Context, org, pipelineSlug, buildNumber string, ac buildkite.AnnotationCreate) (buildkite.Annotation, *buildkite.Response, error)
+	Delete(ctx context.Context, org, pipelineSlug, buildNumber, annotationID string) (*buildkite.Response, error)
+}
+
+// AnnotationsClientAdapter adds the annotation endpoints which go-buildkite doesn't expose to the annotations service
+type AnnotationsClientAdapter struct {
+	*buildkite.Client
+}
+
+// ListByBuild implements AnnotationsClient
+func (a *AnnotationsClientAdapter) ListByBuild(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error) {
+	return a.Annotations.ListByBuild(ctx, org, pipelineSlug, buildNumber, opts)
+}
+
+// Create implements AnnotationsClient
+func (a *AnnotationsClientAdapter) Create(ctx context.Context, org, pipelineSlug, buildNumber string, ac buildkite.AnnotationCreate) (buildkite.Annotation, *buildkite.Response, error) {
+	return a.Annotations.Create(ctx, org, pipelineSlug, buildNumber, ac)
+}
+
+// Delete implements AnnotationsClient
+func (a *AnnotationsClientAdapter) Delete(ctx context.Context, org, pipelineSlug, buildNumber, annotationID string) (*buildkite.Response, error) {
+	u := fmt.Sprintf("v2/organizations/%s/pipelines/%s/builds/%s/annotations/%s", org, pipelineSlug, buildNumber, annotationID)
+	req, err := a.NewRequest(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Do(req, nil)
+}
+
+// MarkdownAnnotation is an annotation with its rendered HTML body converted to Markdown
+type MarkdownAnnotation struct {
+	ID           string               `json:"id,omitempty"`
+	Context      string               `json:"context,omitempty"`
+	Style        string               `json:"style,omitempty"`
+	BodyMarkdown string               `json:"body_markdown"`
+	CreatedAt    *buildkite.Timestamp `json:"created_at,omitempty"`
+	UpdatedAt    *buildkite.Timestamp `json:"updated_at,omitempty"`
 }
 
 // ListAnnotations returns an MCP tool + handler pair that lists annotations for a build.
 func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_annotations",
-			mcp.WithDescription("List all annotations for a build, including their context, style (success/info/warning/error), rendered HTML content, and creation timestamps"),
+			mcp.WithDescription("List all annotations for a build, including their context, style (success/info/warning/error), content as rendered HTML or compact Markdown, and creation timestamps"),
 			mcp.WithString("org",
 				mcp.Required(),
 				mcp.Description("The organization slug for the owner of the pipeline"),
@@ -35,6 +73,17 @@ func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.To
 				mcp.Required(),
 				mcp.Description("The build number"),
 			),
+			mcp.WithString("format",
+				mcp.Description("The format of the annotation body. 'markdown' converts the rendered HTML to compact Markdown, preserving tables, code blocks and links (default html)"),
+				mcp.Enum("html", "markdown"),
+			),
+			mcp.WithString("style",
+				mcp.Description("Only return annotations with this style"),
+				mcp.Enum("success", "info", "warning", "error"),
+			),
+			mcp.WithString("context",
+				mcp.Description("Only return the annotation with this context"),
+			),
 			withPagination(),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        "List Annotations",
@@ -59,6 +108,10 @@ func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.To
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			format := request.GetString("format", "html")
+			styleFilter := request.GetString("style", "")
+			contextFilter := request.GetString("context", "")
+
 			paginationParams, err := optionalPaginationParams(request)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
@@ -68,6 +121,9 @@ func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.To
 				attribute.String("org", org),
 				attribute.String("pipeline_slug", pipelineSlug),
 				attribute.String("build_number", buildNumber),
+				attribute.String("format", format),
+				attribute.String("style", styleFilter),
+				attribute.String("context", contextFilter),
 				attribute.Int("page", paginationParams.Page),
 				attribute.Int("per_page", paginationParams.PerPage),
 			)
@@ -76,25 +132,50 @@ func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.To
 				ListOptions: paginationParams,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list annotations", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
+				return apiErrorResult("list annotations", resp, nil), nil
+			}
+
+			// the API has no style or context filters, so apply them to the page we were given
+			filtered := make([]buildkite.Annotation, 0, len(annotations))
+			for _, annotation := range annotations {
+				if styleFilter != "" && annotation.Style != styleFilter {
+					continue
 				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to list annotations: %s", string(body))), nil
+				if contextFilter != "" && annotation.Context != contextFilter {
+					continue
+				}
+				filtered = append(filtered, annotation)
 			}
 
-			result := PaginatedResult[buildkite.Annotation]{
-				Items: annotations,
-				Headers: map[string]string{
-					"Link": resp.Header.Get("Link"),
-				},
+			headers := map[string]string{
+				"Link": resp.Header.Get("Link"),
 			}
 
-			r, err := json.Marshal(&result)
+			var r []byte
+			if format == "markdown" {
+				items := make([]MarkdownAnnotation, 0, len(filtered))
+				for _, annotation := range filtered {
+					body, err := markdown.FromHTML(annotation.BodyHTML)
+					if err != nil {
+						return nil, fmt.Errorf("failed to convert annotation %s to markdown: %w", annotation.ID, err)
+					}
+					items = append(items, MarkdownAnnotation{
+						ID:           annotation.ID,
+						Context:      annotation.Context,
+						Style:        annotation.Style,
+						BodyMarkdown: body,
+						CreatedAt:    annotation.CreatedAt,
+						UpdatedAt:    annotation.UpdatedAt,
+					})
+				}
+				r, err = json.Marshal(&PaginatedResult[MarkdownAnnotation]{Items: items, Headers: headers})
+			} else {
+				r, err = json.Marshal(&PaginatedResult[buildkite.Annotation]{Items: filtered, Headers: headers})
+			}
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal annotations: %w", err)
 			}
@@ -102,3 +183,176 @@ func ListAnnotations(ctx context.Context, client AnnotationsClient) (tool mcp.To
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// CreateAnnotation returns an MCP tool + handler pair that creates or appends to an annotation on a build.
+func CreateAnnotation(ctx context.Context, client AnnotationsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_annotation",
+			mcp.WithDescription("Create an annotation on a build, or update an existing annotation with the same context. The body is rendered as Markdown at the top of the build page"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The build number"),
+			),
+			mcp.WithString("body",
+				mcp.Required(),
+				mcp.Description("The annotation content, in Markdown or HTML"),
+			),
+			mcp.WithString("context",
+				mcp.Description("A unique identifier for the annotation. Creating an annotation with an existing context replaces it, or appends to it when append is true (default 'default')"),
+			),
+			mcp.WithString("style",
+				mcp.Description("The visual style of the annotation (default info)"),
+				mcp.Enum("success", "info", "warning", "error"),
+			),
+			mcp.WithBoolean("append",
+				mcp.Description("Append the body to an existing annotation with the same context instead of replacing it"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Create Annotation",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.CreateAnnotation")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			body, err := request.RequireString("body")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			annotationContext := request.GetString("context", "")
+			style := request.GetString("style", "")
+			appendBody := request.GetBool("append", false)
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.String("context", annotationContext),
+				attribute.String("style", style),
+				attribute.Bool("append", appendBody),
+			)
+
+			annotation, resp, err := client.Create(ctx, org, pipelineSlug, buildNumber, buildkite.AnnotationCreate{
+				Body:    body,
+				Context: annotationContext,
+				Style:   style,
+				Append:  appendBody,
+			})
+			if err != nil {
+				return apiErrorResult("create annotation", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+				return apiErrorResult("create annotation", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&annotation)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal annotation: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// DeleteAnnotation returns an MCP tool + handler pair that removes an annotation from a build.
+func DeleteAnnotation(ctx context.Context, client AnnotationsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_annotation",
+			mcp.WithDescription("Delete an annotation from a build by its ID"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The build number"),
+			),
+			mcp.WithString("annotation_id",
+				mcp.Required(),
+				mcp.Description("The ID of the annotation, as returned by list_annotations"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Delete Annotation",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.DeleteAnnotation")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			annotationID, err := request.RequireString("annotation_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.String("annotation_id", annotationID),
+			)
+
+			resp, err := client.Delete(ctx, org, pipelineSlug, buildNumber, annotationID)
+			if err != nil {
+				return apiErrorResult("delete annotation", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+				return apiErrorResult("delete annotation", resp, nil), nil
+			}
+
+			r, err := json.Marshal(map[string]any{
+				"annotation_id": annotationID,
+				"deleted":       true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal delete annotation response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
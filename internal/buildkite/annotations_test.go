@@ -12,6 +12,8 @@ import (
 type MockAnnotationsClient struct {
 	ListByBuildFunc func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error)
 	GetFunc         func(ctx context.Context, org, pipelineSlug, buildNumber, id string) (buildkite.Annotation, *buildkite.Response, error)
+	CreateFunc      func(ctx context.Context, org, pipelineSlug, buildNumber string, ac buildkite.AnnotationCreate) (buildkite.Annotation, *buildkite.Response, error)
+	DeleteFunc      func(ctx context.Context, org, pipelineSlug, buildNumber, annotationID string) (*buildkite.Response, error)
 }
 
 func (m *MockAnnotationsClient) ListByBuild(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error) {
@@ -21,6 +23,20 @@ func (m *MockAnnotationsClient) ListByBuild(ctx context.Context, org, pipelineSl
 	return nil, nil, nil
 }
 
+func (m *MockAnnotationsClient) Create(ctx context.Context, org, pipelineSlug, buildNumber string, ac buildkite.AnnotationCreate) (buildkite.Annotation, *buildkite.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org, pipelineSlug, buildNumber, ac)
+	}
+	return buildkite.Annotation{}, nil, nil
+}
+
+func (m *MockAnnotationsClient) Delete(ctx context.Context, org, pipelineSlug, buildNumber, annotationID string) (*buildkite.Response, error) {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, org, pipelineSlug, buildNumber, annotationID)
+	}
+	return nil, nil
+}
+
 var _ AnnotationsClient = (*MockAnnotationsClient)(nil)
 
 func TestListAnnotations(t *testing.T) {
@@ -61,3 +77,125 @@ func TestListAnnotations(t *testing.T) {
 
 	assert.Equal(`{"headers":{"Link":""},"items":[{"id":"1","body_html":"Test annotation 1"},{"id":"2","body_html":"Test annotation 2"}]}`, textContent.Text)
 }
+
+func TestCreateAnnotation(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	client := &MockAnnotationsClient{
+		CreateFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, ac buildkite.AnnotationCreate) (buildkite.Annotation, *buildkite.Response, error) {
+			assert.Equal(buildkite.AnnotationCreate{
+				Body:    "**Looks good**",
+				Context: "review",
+				Style:   "success",
+				Append:  true,
+			}, ac)
+			return buildkite.Annotation{
+					ID:       "1",
+					Context:  ac.Context,
+					Style:    ac.Style,
+					BodyHTML: "<p><strong>Looks good</strong></p>",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 201,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := CreateAnnotation(ctx, client)
+	assert.NotNil(tool)
+	assert.False(*tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "1",
+		"body":          "**Looks good**",
+		"context":       "review",
+		"style":         "success",
+		"append":        true,
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	textContent := getTextResult(t, result)
+
+	assert.Equal(`{"id":"1","context":"review","style":"success","body_html":"\u003cp\u003e\u003cstrong\u003eLooks good\u003c/strong\u003e\u003c/p\u003e"}`, textContent.Text)
+}
+
+func TestDeleteAnnotation(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	client := &MockAnnotationsClient{
+		DeleteFunc: func(ctx context.Context, org, pipelineSlug, buildNumber, annotationID string) (*buildkite.Response, error) {
+			assert.Equal("abc", annotationID)
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := DeleteAnnotation(ctx, client)
+	assert.NotNil(tool)
+	assert.True(*tool.Annotations.DestructiveHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "1",
+		"annotation_id": "abc",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	textContent := getTextResult(t, result)
+
+	assert.Equal(`{"annotation_id":"abc","deleted":true}`, textContent.Text)
+}
+
+func TestListAnnotationsAsMarkdown(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	client := &MockAnnotationsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error) {
+			return []buildkite.Annotation{
+					{
+						ID:       "1",
+						Context:  "coverage",
+						Style:    "info",
+						BodyHTML: "<p>Coverage is 80%</p>",
+					},
+					{
+						ID:       "2",
+						Context:  "junit",
+						Style:    "error",
+						BodyHTML: "<h4>Failures</h4><ul><li><code>TestWidget</code> failed</li></ul>",
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := ListAnnotations(ctx, client)
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "1",
+		"format":        "markdown",
+		"style":         "error",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	textContent := getTextResult(t, result)
+
+	assert.Equal(`{"headers":{"Link":""},"items":[{"id":"2","context":"junit","style":"error","body_markdown":"#### Failures\n\n- `+"`TestWidget`"+` failed"}]}`, textContent.Text)
+}
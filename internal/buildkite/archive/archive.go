@@ -0,0 +1,149 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// MaxZipSize is the largest zip archive which will be spooled to disk. Zip
+// archives keep their index at the end of the file so, unlike tar, they can't
+// be read as a stream.
+const MaxZipSize = 512 << 20
+
+var (
+	// ErrStop can be returned from a WalkFunc to end the walk early without an error
+	ErrStop = errors.New("stop walking archive")
+
+	// ErrUnsupportedFormat is returned when the data is not a tar, tar.gz or zip archive
+	ErrUnsupportedFormat = errors.New("unsupported archive format, expected tar, tar.gz or zip")
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+)
+
+// Entry describes a single file or directory within an archive
+type Entry struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir,omitempty"`
+}
+
+// WalkFunc is called for each entry in an archive with a reader for its contents,
+// which is only valid until the function returns
+type WalkFunc func(entry Entry, r io.Reader) error
+
+// Walk detects the format of the archive in r and calls fn for each entry in order.
+func Walk(r io.Reader, fn WalkFunc) error {
+	br := bufio.NewReaderSize(r, 512)
+
+	header, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return fmt.Errorf("failed to read archive header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("failed to read gzip stream: %w", err)
+		}
+		defer gz.Close()
+
+		return Walk(gz, fn)
+	case bytes.HasPrefix(header, zipMagic):
+		return walkZip(br, fn)
+	case len(header) >= 262 && bytes.Equal(header[257:262], tarMagic):
+		return walkTar(br, fn)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func walkTar(r io.Reader, fn WalkFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		entry := Entry{
+			Name:    hdr.Name,
+			Size:    hdr.Size,
+			ModTime: hdr.ModTime.UTC(),
+			IsDir:   hdr.Typeflag == tar.TypeDir,
+		}
+
+		if err := fn(entry, tr); err != nil {
+			return stopped(err)
+		}
+	}
+}
+
+func walkZip(r io.Reader, fn WalkFunc) error {
+	spool, err := os.CreateTemp("", "buildkite-artifact-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+
+	size, err := io.Copy(spool, io.LimitReader(r, MaxZipSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to download zip archive: %w", err)
+	}
+	if size > MaxZipSize {
+		return fmt.Errorf("zip archive is larger than %d bytes", MaxZipSize)
+	}
+
+	zr, err := zip.NewReader(spool, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		entry := Entry{
+			Name:    f.Name,
+			Size:    int64(f.UncompressedSize64),
+			ModTime: f.Modified.UTC(),
+			IsDir:   f.FileInfo().IsDir(),
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+		}
+
+		err = fn(entry, rc)
+		_ = rc.Close()
+		if err != nil {
+			return stopped(err)
+		}
+	}
+
+	return nil
+}
+
+func stopped(err error) error {
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+	return err
+}
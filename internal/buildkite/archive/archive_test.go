@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = []struct {
+	name    string
+	content string
+}{
+	{"reports/", ""},
+	{"reports/junit.xml", "<testsuite/>"},
+	{"coverage.out", "mode: set\n"},
+}
+
+func createTar(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range testFiles {
+		hdr := &tar.Header{Name: f.name, Mode: 0600, Size: int64(len(f.content)), Typeflag: tar.TypeReg}
+		if f.content == "" {
+			hdr.Typeflag = tar.TypeDir
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func createZip(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range testFiles {
+		w, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		data func(t *testing.T) []byte
+	}{
+		{"tar", createTar},
+		{"tar.gz", func(t *testing.T) []byte { return gzipped(t, createTar(t)) }},
+		{"zip", createZip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			var names []string
+			contents := map[string]string{}
+			err := Walk(bytes.NewReader(tt.data(t)), func(entry Entry, r io.Reader) error {
+				names = append(names, entry.Name)
+				if entry.IsDir {
+					return nil
+				}
+				data, err := io.ReadAll(r)
+				assert.NoError(err)
+				assert.Equal(int64(len(data)), entry.Size)
+				contents[entry.Name] = string(data)
+				return nil
+			})
+			assert.NoError(err)
+			assert.Equal([]string{"reports/", "reports/junit.xml", "coverage.out"}, names)
+			assert.Equal(map[string]string{"reports/junit.xml": "<testsuite/>", "coverage.out": "mode: set\n"}, contents)
+		})
+	}
+}
+
+func TestWalkStop(t *testing.T) {
+	assert := require.New(t)
+
+	var names []string
+	err := Walk(bytes.NewReader(createTar(t)), func(entry Entry, r io.Reader) error {
+		names = append(names, entry.Name)
+		if entry.Name == "reports/junit.xml" {
+			return ErrStop
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"reports/", "reports/junit.xml"}, names)
+}
+
+func TestWalkUnsupported(t *testing.T) {
+	assert := require.New(t)
+
+	err := Walk(bytes.NewReader([]byte("just some text")), func(entry Entry, r io.Reader) error {
+		return nil
+	})
+	assert.ErrorIs(err, ErrUnsupportedFormat)
+
+	err = Walk(bytes.NewReader(gzipped(t, []byte("a compressed log"))), func(entry Entry, r io.Reader) error {
+		return nil
+	})
+	assert.ErrorIs(err, ErrUnsupportedFormat)
+}
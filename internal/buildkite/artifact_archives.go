@@ -0,0 +1,251 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/archive"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxArchiveEntries caps how many entries list_artifact_archive collects before paginating
+const maxArchiveEntries = 10000
+
+// errArchiveClosed is used to stop the download once the archive has been read far enough
+var errArchiveClosed = errors.New("archive closed")
+
+// ArchiveEntryContent is the decoded content of a single file within an archive artifact
+type ArchiveEntryContent struct {
+	Path string `json:"path"`
+	DecodedContent
+}
+
+func ListArtifactArchive(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_artifact_archive",
+			mcp.WithDescription("List the files inside a tar, tar.gz or zip artifact with their sizes, without downloading the whole archive into memory"),
+			mcp.WithString("url",
+				mcp.Required(),
+				mcp.Description("The URL of the archive artifact"),
+			),
+			mcp.WithString("prefix",
+				mcp.Description("Only list entries whose path starts with this prefix"),
+			),
+			withClientSidePagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "List Artifact Archive",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ListArtifactArchive")
+			defer span.End()
+
+			url, err := request.RequireString("url")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			prefix := request.GetString("prefix", "")
+			paginationParams := getClientSidePaginationParams(request)
+
+			span.SetAttributes(
+				attribute.String("url", url),
+				attribute.String("prefix", prefix),
+				attribute.Int("page", paginationParams.Page),
+				attribute.Int("per_page", paginationParams.PerPage),
+			)
+
+			entries := make([]archive.Entry, 0)
+			err = walkArtifactArchive(ctx, client, url, func(entry archive.Entry, r io.Reader) error {
+				if !strings.HasPrefix(entry.Name, prefix) {
+					return nil
+				}
+				if len(entries) == maxArchiveEntries {
+					return fmt.Errorf("archive has more than %d entries, narrow the listing with prefix", maxArchiveEntries)
+				}
+				entries = append(entries, entry)
+				return nil
+			})
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			span.SetAttributes(attribute.Int("entries", len(entries)))
+
+			result := applyClientSidePagination(entries, paginationParams)
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal archive entries: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func ReadArtifactArchiveEntry(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("read_artifact_archive_entry",
+			mcp.WithDescription("Extract a single file from a tar, tar.gz or zip artifact. The archive is streamed and stops downloading once the file is found. Content is decoded the same way as get_artifact"),
+			mcp.WithString("url",
+				mcp.Required(),
+				mcp.Description("The URL of the archive artifact"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("The path of the file within the archive, as returned by list_artifact_archive"),
+			),
+			mcp.WithNumber("max_size",
+				mcp.Description("Maximum number of bytes to read from the file (default 1048576, max 10485760)"),
+				mcp.Min(1),
+				mcp.Max(maxArtifactSize),
+			),
+			mcp.WithNumber("line_start",
+				mcp.Description("For text files, the first line to return (1-based, default 1)"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("line_end",
+				mcp.Description("For text files, the last line to return (inclusive, default the last line)"),
+				mcp.Min(1),
+			),
+			mcp.WithString("grep",
+				mcp.Description("For text files, only return lines matching this regular expression, with their line numbers"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Read Artifact Archive Entry",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ReadArtifactArchiveEntry")
+			defer span.End()
+
+			url, err := request.RequireString("url")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			path, err := request.RequireString("path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			maxSize := int64(min(request.GetInt("max_size", defaultArtifactSize), maxArtifactSize))
+			lineStart, lineEnd, grep, grepRegexp, err := getTextSelectionParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("url", url),
+				attribute.String("path", path),
+				attribute.Int64("max_size", maxSize),
+				attribute.Int("line_start", lineStart),
+				attribute.Int("line_end", lineEnd),
+				attribute.String("grep", grep),
+			)
+
+			var (
+				found  bool
+				size   int64
+				buffer = &cappedBuffer{limit: maxSize}
+			)
+			err = walkArtifactArchive(ctx, client, url, func(entry archive.Entry, r io.Reader) error {
+				if strings.TrimPrefix(entry.Name, "./") != strings.TrimPrefix(path, "./") {
+					return nil
+				}
+				if entry.IsDir {
+					return fmt.Errorf("%s is a directory, use list_artifact_archive to see its contents", path)
+				}
+
+				found = true
+				if _, err := io.Copy(buffer, r); err != nil && !errors.Is(err, errBufferFull) {
+					return fmt.Errorf("failed to read %s: %w", path, err)
+				}
+				size = max(entry.Size, int64(buffer.Len()))
+				return archive.ErrStop
+			})
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			if !found {
+				return mcp.NewToolResultError(fmt.Sprintf("%s not found in archive", path)), nil
+			}
+
+			data := buffer.Bytes()
+			mimeType := detectArtifactMimeType(data)
+
+			span.SetAttributes(
+				attribute.String("mime_type", mimeType),
+				attribute.Int64("size", size),
+			)
+
+			if buffer.Truncated() && !isTextMimeType(mimeType) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s is a %s binary larger than max_size (%d bytes)", path, mimeType, maxSize)), nil
+			}
+
+			result := ArchiveEntryContent{
+				Path: path,
+				DecodedContent: DecodedContent{
+					MimeType:  mimeType,
+					Size:      size,
+					Truncated: buffer.Truncated(),
+				},
+			}
+			decodeContent(&result.DecodedContent, data, lineStart, lineEnd, grepRegexp)
+
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal archive entry: %w", err)
+			}
+
+			if strings.HasPrefix(mimeType, "image/") {
+				return mcp.NewToolResultImage(string(r), base64.StdEncoding.EncodeToString(data), mimeType), nil
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// walkArtifactArchive streams an artifact download through archive.Walk so only the entry being
+// read is held in memory. If fn stops the walk early the rest of the download is abandoned.
+func walkArtifactArchive(ctx context.Context, client ArtifactsClient, url string, fn archive.WalkFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	downloaded := make(chan error, 1)
+
+	go func() {
+		resp, err := client.DownloadArtifactByURL(ctx, url, pw)
+		switch {
+		case errors.Is(err, errArchiveClosed) || errors.Is(err, context.Canceled):
+		case err != nil:
+			err = newAPIError("download artifact", resp, err)
+		case resp.StatusCode != http.StatusOK:
+			err = newAPIError("download artifact", resp, nil)
+		}
+		_ = pw.CloseWithError(err)
+		downloaded <- err
+	}()
+
+	walkErr := archive.Walk(pr, fn)
+
+	// unblock the download if the walk finished before reading everything
+	cancel()
+	_ = pr.CloseWithError(errArchiveClosed)
+
+	downloadErr := <-downloaded
+	if downloadErr != nil && !errors.Is(downloadErr, errArchiveClosed) && !errors.Is(downloadErr, context.Canceled) {
+		return downloadErr
+	}
+
+	return walkErr
+}
@@ -0,0 +1,216 @@
+package buildkite
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func createTarGz(t *testing.T, files map[string]string, order ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range order {
+		content := files[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf.Bytes()
+}
+
+// streamingArtifactsClient writes the archive in small chunks and stops at the first write error, like the real client
+func streamingArtifactsClient(data []byte, written *int) *MockArtifactsClient {
+	return &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			resp := &buildkite.Response{
+				Response: &http.Response{
+					Status:     "200 OK",
+					StatusCode: 200,
+				},
+			}
+			for chunk := range slices.Chunk(data, 512) {
+				n, err := writer.Write(chunk)
+				*written += n
+				if err != nil {
+					return resp, err
+				}
+			}
+			return resp, nil
+		},
+	}
+}
+
+func TestListArtifactArchive(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	data := createTarGz(t, map[string]string{
+		"reports/junit.xml": "<testsuite/>",
+		"reports/lint.txt":  "ok",
+		"coverage.out":      "mode: set\n",
+	}, "reports/junit.xml", "reports/lint.txt", "coverage.out")
+
+	var written int
+	tool, handler := ListArtifactArchive(ctx, streamingArtifactsClient(data, &written))
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"url":    "https://example.com/artifact",
+		"prefix": "reports/",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var entries ClientSidePaginatedResult[struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	}]
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &entries))
+	assert.Equal(2, entries.Total)
+	assert.Equal("reports/junit.xml", entries.Items[0].Name)
+	assert.Equal(int64(12), entries.Items[0].Size)
+	assert.Equal("reports/lint.txt", entries.Items[1].Name)
+}
+
+func TestReadArtifactArchiveEntry(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	data := createTarGz(t, map[string]string{
+		"build.log": "line one\nerror: boom\nline three\n",
+		"large.bin": strings.Repeat("x", 1<<20),
+	}, "build.log", "large.bin")
+
+	var written int
+	tool, handler := ReadArtifactArchiveEntry(ctx, streamingArtifactsClient(data, &written))
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"url":  "https://example.com/artifact",
+		"path": "build.log",
+		"grep": "error",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	text := getTextResult(t, result).Text
+	assert.Contains(text, `"path":"build.log"`)
+	assert.Contains(text, `"encoding":"utf-8"`)
+	assert.Contains(text, `"matches":[{"line":2,"text":"error: boom"}]`)
+
+	// the download is abandoned once the entry has been read
+	assert.Less(written, len(data))
+}
+
+func TestReadArtifactArchiveEntry_NotFound(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	data := createTarGz(t, map[string]string{"build.log": "hello"}, "build.log")
+
+	var written int
+	_, handler := ReadArtifactArchiveEntry(ctx, streamingArtifactsClient(data, &written))
+
+	request := createMCPRequest(t, map[string]any{
+		"url":  "https://example.com/artifact",
+		"path": "missing.txt",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Equal("missing.txt not found in archive", getTextResult(t, result).Text)
+}
+
+func TestReadArtifactArchiveEntry_InvalidLineRange(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	data := createTarGz(t, map[string]string{"build.log": "one\ntwo\n"}, "build.log")
+
+	var written int
+	_, handler := ReadArtifactArchiveEntry(ctx, streamingArtifactsClient(data, &written))
+
+	for _, params := range []map[string]any{
+		{"line_start": float64(0)},
+		{"line_end": float64(-1)},
+		{"line_start": float64(2), "line_end": float64(1)},
+	} {
+		params["url"] = "https://example.com/artifact"
+		params["path"] = "build.log"
+		result, err := handler(ctx, createMCPRequest(t, params))
+		assert.NoError(err)
+		assert.True(result.IsError)
+		assert.Contains(getTextResult(t, result).Text, "line_")
+	}
+
+	// nothing is downloaded for a request which can't be served
+	assert.Zero(written)
+}
+
+func TestListArtifactArchive_NotAnArchive(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var written int
+	_, handler := ListArtifactArchive(ctx, streamingArtifactsClient([]byte("plain text"), &written))
+
+	request := createMCPRequest(t, map[string]any{
+		"url": "https://example.com/artifact",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "unsupported archive format")
+}
+
+func TestListArtifactArchive_DownloadError(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	client := &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			return &buildkite.Response{
+				Response: &http.Response{
+					Status:     "404 Not Found",
+					StatusCode: 404,
+					Body:       io.NopCloser(strings.NewReader(`{"message":"No artifact found"}`)),
+				},
+			}, nil
+		},
+	}
+
+	_, handler := ListArtifactArchive(ctx, client)
+
+	request := createMCPRequest(t, map[string]any{
+		"url": "https://example.com/artifact",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+
+	var apiErr APIError
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &apiErr))
+	assert.Equal("download artifact", apiErr.Operation)
+	assert.Equal(ErrorNotFound, apiErr.Category)
+	assert.Equal("No artifact found", apiErr.Message)
+}
@@ -5,9 +5,15 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -16,8 +22,27 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	// defaultArtifactSize is the default number of bytes read from an artifact
+	defaultArtifactSize = 1 << 20
+	// maxArtifactSize is the most an artifact tool will read into memory
+	maxArtifactSize = 10 << 20
+	// maxArtifactListPages caps how many pages of artifacts are fetched when listing a build
+	maxArtifactListPages = 10
+	// artifactDownloadConcurrency limits how many artifacts are downloaded at once
+	artifactDownloadConcurrency = 5
+)
+
+// ArtifactList is a page of a build's artifacts. Truncated is set when the build had more artifacts
+// than are fetched, so the listing and its filters only cover the first of them.
+type ArtifactList struct {
+	ClientSidePaginatedResult[buildkite.Artifact]
+	Truncated bool `json:"truncated,omitempty"`
+}
+
 type ArtifactsClient interface {
 	ListByBuild(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error)
+	ListByJob(ctx context.Context, org, pipelineSlug, buildNumber, jobID string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error)
 	DownloadArtifactByURL(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error)
 }
 
@@ -31,6 +56,11 @@ func (a *BuildkiteClientAdapter) ListByBuild(ctx context.Context, org, pipelineS
 	return a.Artifacts.ListByBuild(ctx, org, pipelineSlug, buildNumber, opts)
 }
 
+// ListByJob implements ArtifactsClient
+func (a *BuildkiteClientAdapter) ListByJob(ctx context.Context, org, pipelineSlug, buildNumber, jobID string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+	return a.Artifacts.ListByJob(ctx, org, pipelineSlug, buildNumber, jobID, opts)
+}
+
 // DownloadArtifactByURL implements ArtifactsClient
 func (a *BuildkiteClientAdapter) DownloadArtifactByURL(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
 	return a.Artifacts.DownloadArtifactByURL(ctx, url, writer)
@@ -38,7 +68,7 @@ func (a *BuildkiteClientAdapter) DownloadArtifactByURL(ctx context.Context, url
 
 func ListArtifacts(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_artifacts",
-			mcp.WithDescription("List all artifacts for a build across all jobs, including file details, paths, sizes, MIME types, and download URLs"),
+			mcp.WithDescription("List the artifacts for a build, or a single job, including file details, paths, sizes, MIME types, and download URLs. Artifacts can be filtered by path glob, size and state. Only the first 1000 artifacts of a build are fetched, and the result is marked truncated when there are more"),
 			mcp.WithString("org",
 				mcp.Required(),
 				mcp.Description("The organization slug for the owner of the pipeline"),
@@ -51,10 +81,25 @@ func ListArtifacts(ctx context.Context, client ArtifactsClient) (tool mcp.Tool,
 				mcp.Required(),
 				mcp.Description("The build number"),
 			),
-			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        "Artifact List",
-				ReadOnlyHint: mcp.ToBoolPtr(true),
-			}),
+			mcp.WithString("job_uuid",
+				mcp.Description("Only list artifacts uploaded by this job"),
+			),
+			mcp.WithString("path_glob",
+				mcp.Description("Only list artifacts whose path matches this glob, e.g. 'coverage/*.json'. '*' does not match '/'"),
+			),
+			mcp.WithNumber("min_size",
+				mcp.Description("Only list artifacts of at least this many bytes"),
+				mcp.Min(0),
+			),
+			mcp.WithNumber("max_size",
+				mcp.Description("Only list artifacts of at most this many bytes"),
+				mcp.Min(0),
+			),
+			mcp.WithString("state",
+				mcp.Description("Only list artifacts in this state"),
+				mcp.Enum("new", "error", "finished", "deleted", "expired"),
+			),
+			withClientSidePagination(),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        "List Artifacts",
 				ReadOnlyHint: mcp.ToBoolPtr(true),
@@ -79,42 +124,50 @@ func ListArtifacts(ctx context.Context, client ArtifactsClient) (tool mcp.Tool,
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			paginationParams, err := optionalPaginationParams(request)
-			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+			jobUUID := request.GetString("job_uuid", "")
+			filter := artifactFilter{
+				pathGlob: request.GetString("path_glob", ""),
+				minSize:  int64(request.GetInt("min_size", 0)),
+				maxSize:  int64(request.GetInt("max_size", 0)),
+				state:    request.GetString("state", ""),
 			}
+			if _, err := path.Match(filter.pathGlob, ""); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid path_glob: %s", err)), nil
+			}
+
+			paginationParams := getClientSidePaginationParams(request)
 
 			span.SetAttributes(
 				attribute.String("org", org),
 				attribute.String("pipeline_slug", pipelineSlug),
 				attribute.String("build_number", buildNumber),
+				attribute.String("job_uuid", jobUUID),
+				attribute.String("path_glob", filter.pathGlob),
+				attribute.Int64("min_size", filter.minSize),
+				attribute.Int64("max_size", filter.maxSize),
+				attribute.String("state", filter.state),
 				attribute.Int("page", paginationParams.Page),
 				attribute.Int("per_page", paginationParams.PerPage),
 			)
 
-			artifacts, resp, err := client.ListByBuild(ctx, org, pipelineSlug, buildNumber, &buildkite.ArtifactListOptions{
-				ListOptions: paginationParams,
-			})
+			artifacts, truncated, err := listAllArtifacts(ctx, client, org, pipelineSlug, buildNumber, jobUUID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return toolErrorResult(err), nil
 			}
 
-			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
+			matched := make([]buildkite.Artifact, 0, len(artifacts))
+			for _, artifact := range artifacts {
+				if filter.matches(artifact) {
+					matched = append(matched, artifact)
 				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
 			}
 
-			result := PaginatedResult[buildkite.Artifact]{
-				Items: artifacts,
-				Headers: map[string]string{
-					"Link": resp.Header.Get("Link"),
-				},
+			result := ArtifactList{
+				ClientSidePaginatedResult: applyClientSidePagination(matched, paginationParams),
+				Truncated:                 truncated,
 			}
 
-			r, err := json.Marshal(result)
+			r, err := json.Marshal(&result)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal artifacts: %w", err)
 			}
@@ -122,13 +175,148 @@ func ListArtifacts(ctx context.Context, client ArtifactsClient) (tool mcp.Tool,
 		}
 }
 
+// artifactFilter narrows an artifact listing client side, as the API has no filters of its own
+type artifactFilter struct {
+	pathGlob string
+	minSize  int64
+	maxSize  int64
+	state    string
+}
+
+func (f artifactFilter) matches(artifact buildkite.Artifact) bool {
+	if f.pathGlob != "" {
+		if matched, _ := path.Match(f.pathGlob, artifact.Path); !matched {
+			return false
+		}
+	}
+	if f.minSize > 0 && artifact.FileSize < f.minSize {
+		return false
+	}
+	if f.maxSize > 0 && artifact.FileSize > f.maxSize {
+		return false
+	}
+	if f.state != "" && artifact.State != f.state {
+		return false
+	}
+	return true
+}
+
+// listAllArtifacts fetches every page of artifacts for a build, or a single job when jobID is set,
+// up to maxArtifactListPages. It reports whether pages were left unfetched.
+func listAllArtifacts(ctx context.Context, client ArtifactsClient, org, pipelineSlug, buildNumber, jobID string) ([]buildkite.Artifact, bool, error) {
+	var all []buildkite.Artifact
+
+	options := &buildkite.ArtifactListOptions{
+		ListOptions: buildkite.ListOptions{PerPage: 100},
+	}
+	for range maxArtifactListPages {
+		var (
+			artifacts []buildkite.Artifact
+			resp      *buildkite.Response
+			err       error
+		)
+		if jobID != "" {
+			artifacts, resp, err = client.ListByJob(ctx, org, pipelineSlug, buildNumber, jobID, options)
+		} else {
+			artifacts, resp, err = client.ListByBuild(ctx, org, pipelineSlug, buildNumber, options)
+		}
+		if err != nil {
+			return nil, false, newAPIError("list artifacts", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, false, newAPIError("list artifacts", resp, nil)
+		}
+
+		all = append(all, artifacts...)
+
+		if resp.NextPage == 0 {
+			return all, false, nil
+		}
+		options.Page = resp.NextPage
+	}
+
+	return all, true, nil
+}
+
+// downloadArtifacts fetches the artifacts concurrently, each up to maxArtifactSize. The content of an
+// artifact which couldn't be downloaded is nil and the reason is included in the returned errors.
+func downloadArtifacts(ctx context.Context, client ArtifactsClient, artifacts []buildkite.Artifact) ([][]byte, []string) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []string
+		contents = make([][]byte, len(artifacts))
+		limit    = make(chan struct{}, artifactDownloadConcurrency)
+	)
+
+	for i, artifact := range artifacts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			content, err := downloadArtifact(ctx, client, artifact)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Sprintf("%s: %s", artifact.Path, err))
+				return
+			}
+			contents[i] = content
+		}()
+	}
+	wg.Wait()
+
+	return contents, errs
+}
+
+func downloadArtifact(ctx context.Context, client ArtifactsClient, artifact buildkite.Artifact) ([]byte, error) {
+	if artifact.FileSize > maxArtifactSize {
+		return nil, fmt.Errorf("%d bytes is larger than %d bytes", artifact.FileSize, maxArtifactSize)
+	}
+
+	buffer, resp, err := downloadArtifactCapped(ctx, client, artifact.DownloadURL, maxArtifactSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	// the artifact's recorded size can be missing or wrong, so the download is still capped
+	if buffer.Truncated() {
+		return nil, fmt.Errorf("larger than %d bytes", maxArtifactSize)
+	}
+
+	return buffer.Bytes(), nil
+}
+
 func GetArtifact(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_artifact",
-			mcp.WithDescription("Get detailed information about a specific artifact including its metadata, file size, SHA-1 hash, and download URL"),
+			mcp.WithDescription("Get the contents of a specific artifact. Text artifacts are returned as UTF-8 and can be narrowed by line range or a regular expression, images are returned as image content, and other binaries are base64 encoded. Artifacts larger than max_size are truncated (text) or refused (binary)"),
 			mcp.WithString("url",
 				mcp.Required(),
 				mcp.Description("The URL of the artifact to get"),
 			),
+			mcp.WithNumber("max_size",
+				mcp.Description("Maximum number of bytes to read from the artifact (default 1048576, max 10485760)"),
+				mcp.Min(1),
+				mcp.Max(maxArtifactSize),
+			),
+			mcp.WithNumber("line_start",
+				mcp.Description("For text artifacts, the first line to return (1-based, default 1)"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("line_end",
+				mcp.Description("For text artifacts, the last line to return (inclusive, default the last line)"),
+				mcp.Min(1),
+			),
+			mcp.WithString("grep",
+				mcp.Description("For text artifacts, only return lines matching this regular expression, with their line numbers"),
+			),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        "Get Artifact",
 				ReadOnlyHint: mcp.ToBoolPtr(true),
@@ -143,29 +331,65 @@ func GetArtifact(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, ha
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			span.SetAttributes(attribute.String("url", url))
-
-			// Use a buffer to capture the artifact data instead of writing directly to stdout
-			var buffer bytes.Buffer
-			resp, err := client.DownloadArtifactByURL(ctx, url, &buffer)
+			maxSize := int64(min(request.GetInt("max_size", defaultArtifactSize), maxArtifactSize))
+			lineStart, lineEnd, grep, grepRegexp, err := getTextSelectionParams(request)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			span.SetAttributes(
+				attribute.String("url", url),
+				attribute.Int64("max_size", maxSize),
+				attribute.Int("line_start", lineStart),
+				attribute.Int("line_end", lineEnd),
+				attribute.String("grep", grep),
+			)
+
+			// Cap how much of the artifact is held in memory, the download stops at the limit
+			buffer, resp, err := downloadArtifactCapped(ctx, client, url, maxSize)
+			if err != nil {
+				return apiErrorResult("get artifact", resp, err), nil
+			}
+
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get artifact: %s", string(body))), nil
+				return apiErrorResult("get artifact", resp, nil), nil
+			}
+
+			data := buffer.Bytes()
+			mimeType := detectArtifactMimeType(data)
+
+			// the full size of a truncated artifact is only known from the response
+			size := int64(len(data))
+			if buffer.Truncated() && resp.ContentLength > size {
+				size = resp.ContentLength
+			}
+
+			span.SetAttributes(
+				attribute.String("mime_type", mimeType),
+				attribute.Int64("size", size),
+			)
+
+			if buffer.Truncated() && !isTextMimeType(mimeType) {
+				return mcp.NewToolResultError(fmt.Sprintf("artifact is a %s binary larger than max_size (%d bytes), download it directly instead", mimeType, maxSize)), nil
 			}
 
-			// Create a response with the artifact data encoded safely for JSON
-			result := map[string]any{
-				"status":     resp.Status,
-				"statusCode": resp.StatusCode,
-				"data":       base64.StdEncoding.EncodeToString(buffer.Bytes()),
-				"encoding":   "base64",
+			result := ArtifactContent{
+				Status:     resp.Status,
+				StatusCode: resp.StatusCode,
+				DecodedContent: DecodedContent{
+					MimeType:  mimeType,
+					Size:      size,
+					Truncated: buffer.Truncated(),
+				},
+			}
+			decodeContent(&result.DecodedContent, data, lineStart, lineEnd, grepRegexp)
+
+			if strings.HasPrefix(mimeType, "image/") {
+				r, err := json.Marshal(result)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal artifact response: %w", err)
+				}
+				return mcp.NewToolResultImage(string(r), base64.StdEncoding.EncodeToString(data), mimeType), nil
 			}
 
 			r, err := json.Marshal(result)
@@ -175,3 +399,186 @@ func GetArtifact(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, ha
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// ArtifactContent is the decoded content of a downloaded artifact
+type ArtifactContent struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"statusCode"`
+	DecodedContent
+}
+
+// DecodedContent is artifact data decoded according to its mime type
+type DecodedContent struct {
+	MimeType   string         `json:"mime_type"`
+	Size       int64          `json:"size"`
+	Truncated  bool           `json:"truncated,omitempty"`
+	Encoding   string         `json:"encoding"`
+	Data       string         `json:"data,omitempty"`
+	Content    string         `json:"content,omitempty"`
+	TotalLines int            `json:"total_lines,omitempty"`
+	LineStart  int            `json:"line_start,omitempty"`
+	LineEnd    int            `json:"line_end,omitempty"`
+	Matches    []ArtifactLine `json:"matches,omitempty"`
+}
+
+// ArtifactLine is a numbered line from a text artifact
+type ArtifactLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// errBufferFull is returned by a cappedBuffer once it has all it will keep, to stop the download
+var errBufferFull = errors.New("buffer full")
+
+// cappedBuffer keeps the first limit bytes written to it. Writing past the limit fails with
+// errBufferFull and calls stop, if set, so the rest of a large artifact isn't downloaded only to be
+// discarded.
+// The buffer isn't embedded, as io.Copy would then use its ReadFrom and skip the limit.
+type cappedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int64
+	stop      func()
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := c.limit - int64(c.buffer.Len())
+	if int64(len(p)) <= remaining {
+		return c.buffer.Write(p)
+	}
+
+	n, _ := c.buffer.Write(p[:max(remaining, 0)])
+	c.truncated = true
+	if c.stop != nil {
+		c.stop()
+	}
+	return n, errBufferFull
+}
+
+// Bytes returns the bytes the buffer kept
+func (c *cappedBuffer) Bytes() []byte {
+	return c.buffer.Bytes()
+}
+
+// Len returns how many bytes the buffer kept
+func (c *cappedBuffer) Len() int {
+	return c.buffer.Len()
+}
+
+// Truncated reports whether more bytes were written than the buffer kept
+func (c *cappedBuffer) Truncated() bool {
+	return c.truncated
+}
+
+// downloadArtifactCapped downloads up to limit bytes of an artifact. go-buildkite reads the rest of
+// the body before returning even when the writer fails, so the request is cancelled once the limit
+// is reached.
+func downloadArtifactCapped(ctx context.Context, client ArtifactsClient, url string, limit int64) (*cappedBuffer, *buildkite.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	buffer := &cappedBuffer{limit: limit, stop: cancel}
+	resp, err := client.DownloadArtifactByURL(ctx, url, buffer)
+	if buffer.Truncated() && errors.Is(err, errBufferFull) {
+		err = nil
+	}
+	return buffer, resp, err
+}
+
+// detectArtifactMimeType sniffs the content type, treating valid UTF-8 without NUL bytes as text
+// since http.DetectContentType reports formats like JSON or JUnit XML inconsistently
+func detectArtifactMimeType(data []byte) string {
+	mimeType := http.DetectContentType(data)
+	if isTextMimeType(mimeType) {
+		return mimeType
+	}
+
+	if mimeType != "application/octet-stream" || bytes.IndexByte(data, 0) != -1 {
+		return mimeType
+	}
+
+	// a truncated download can split a multi-byte rune at the end, so allow for that
+	for cut := 0; cut < utf8.UTFMax && cut <= len(data); cut++ {
+		if utf8.Valid(data[:len(data)-cut]) {
+			return "text/plain; charset=utf-8"
+		}
+	}
+
+	return mimeType
+}
+
+func isTextMimeType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || strings.HasPrefix(mimeType, "application/json") || strings.HasPrefix(mimeType, "application/xml")
+}
+
+// decodeContent returns text as UTF-8, narrowed by applyTextSelection, and other data as base64.
+// Images are left for the caller to attach as image content.
+func decodeContent(result *DecodedContent, data []byte, lineStart, lineEnd int, grep *regexp.Regexp) {
+	switch {
+	case isTextMimeType(result.MimeType):
+		result.Encoding = "utf-8"
+		applyTextSelection(result, string(data), lineStart, lineEnd, grep)
+	case strings.HasPrefix(result.MimeType, "image/"):
+		result.Encoding = "base64"
+	default:
+		result.Encoding = "base64"
+		result.Data = base64.StdEncoding.EncodeToString(data)
+	}
+}
+
+// getTextSelectionParams gets the line range and grep expression used to narrow text content. A
+// line_end of 0 means the last line.
+func getTextSelectionParams(request mcp.CallToolRequest) (lineStart, lineEnd int, grep string, grepRegexp *regexp.Regexp, err error) {
+	lineStart = request.GetInt("line_start", 1)
+	lineEnd = request.GetInt("line_end", 0)
+	grep = request.GetString("grep", "")
+
+	if lineStart < 1 {
+		return 0, 0, "", nil, fmt.Errorf("line_start must be at least 1")
+	}
+	if lineEnd < 0 {
+		return 0, 0, "", nil, fmt.Errorf("line_end must be at least 1")
+	}
+	if lineEnd != 0 && lineEnd < lineStart {
+		return 0, 0, "", nil, fmt.Errorf("line_end must not be before line_start")
+	}
+
+	if grep != "" {
+		grepRegexp, err = regexp.Compile(grep)
+		if err != nil {
+			return 0, 0, "", nil, fmt.Errorf("invalid grep expression: %s", err)
+		}
+	}
+
+	return lineStart, lineEnd, grep, grepRegexp, nil
+}
+
+// applyTextSelection narrows text content to the requested line range and, optionally, the lines matching grep
+func applyTextSelection(result *DecodedContent, text string, lineStart, lineEnd int, grep *regexp.Regexp) {
+	lines := strings.Split(strings.TrimSuffix(strings.ToValidUTF8(text, "\uFFFD"), "\n"), "\n")
+	result.TotalLines = len(lines)
+
+	lineStart = max(lineStart, 1)
+	if lineEnd <= 0 || lineEnd > len(lines) {
+		lineEnd = len(lines)
+	}
+	if lineStart > lineEnd {
+		lineStart = lineEnd + 1
+	}
+	result.LineStart = lineStart
+	result.LineEnd = lineEnd
+
+	selected := lines[lineStart-1 : lineEnd]
+
+	if grep == nil {
+		result.Content = strings.Join(selected, "\n")
+		return
+	}
+
+	result.Matches = make([]ArtifactLine, 0)
+	for i, line := range selected {
+		if grep.MatchString(line) {
+			result.Matches = append(result.Matches, ArtifactLine{Line: lineStart + i, Text: line})
+		}
+	}
+}
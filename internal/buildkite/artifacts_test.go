@@ -3,16 +3,24 @@ package buildkite
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/require"
 )
 
 type MockArtifactsClient struct {
 	ListByBuildFunc           func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error)
+	ListByJobFunc             func(ctx context.Context, org, pipelineSlug, buildNumber, jobID string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error)
 	DownloadArtifactByURLFunc func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error)
 }
 
@@ -23,6 +31,13 @@ func (m *MockArtifactsClient) ListByBuild(ctx context.Context, org, pipelineSlug
 	return nil, nil, nil
 }
 
+func (m *MockArtifactsClient) ListByJob(ctx context.Context, org, pipelineSlug, buildNumber, jobID string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+	if m.ListByJobFunc != nil {
+		return m.ListByJobFunc(ctx, org, pipelineSlug, buildNumber, jobID, opts)
+	}
+	return nil, nil, nil
+}
+
 func (m *MockArtifactsClient) DownloadArtifactByURL(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
 	if m.DownloadArtifactByURLFunc != nil {
 		return m.DownloadArtifactByURLFunc(ctx, url, writer)
@@ -73,6 +88,150 @@ func TestListArtifacts(t *testing.T) {
 	assert.Contains(textContent.Text, `"download_url":"https://example.com/artifact"`)
 }
 
+func TestListArtifacts_Filters(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	mockArtifactsClient := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			assert.Fail("expected the per-job endpoint to be used")
+			return nil, nil, nil
+		},
+		ListByJobFunc: func(ctx context.Context, org, pipelineSlug, buildNumber, jobID string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			assert.Equal("job-uuid", jobID)
+
+			if opts.Page == 0 {
+				return []buildkite.Artifact{
+						{ID: "1", Path: "coverage/unit.json", FileSize: 2048, State: "finished"},
+						{ID: "2", Path: "coverage/nested/e2e.json", FileSize: 2048, State: "finished"},
+						{ID: "3", Path: "coverage/empty.json", FileSize: 10, State: "finished"},
+					}, &buildkite.Response{
+						Response: &http.Response{
+							StatusCode: 200,
+						},
+						NextPage: 2,
+					}, nil
+			}
+
+			assert.Equal(2, opts.Page)
+			return []buildkite.Artifact{
+					{ID: "4", Path: "coverage/integration.json", FileSize: 4096, State: "finished"},
+					{ID: "5", Path: "coverage/failed.json", FileSize: 4096, State: "error"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := ListArtifacts(ctx, mockArtifactsClient)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "test-org",
+		"pipeline_slug": "test-pipeline",
+		"build_number":  "123",
+		"job_uuid":      "job-uuid",
+		"path_glob":     "coverage/*.json",
+		"min_size":      float64(1024),
+		"state":         "finished",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var artifacts ArtifactList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &artifacts))
+	assert.Equal(2, artifacts.Total)
+	assert.Equal("1", artifacts.Items[0].ID)
+	assert.Equal("4", artifacts.Items[1].ID)
+	assert.False(artifacts.Truncated)
+
+	// invalid globs are reported rather than matching nothing
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"org":           "test-org",
+		"pipeline_slug": "test-pipeline",
+		"build_number":  "123",
+		"path_glob":     "[",
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+}
+
+func TestListArtifacts_ZeroPagination(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	mockArtifactsClient := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			return []buildkite.Artifact{
+					{ID: "1", Path: "log.txt", State: "finished"},
+					{ID: "2", Path: "junit.xml", State: "finished"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := ListArtifacts(ctx, mockArtifactsClient)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "test-org",
+		"pipeline_slug": "test-pipeline",
+		"build_number":  "123",
+		"page":          float64(0),
+		"perPage":       float64(0),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var artifacts ArtifactList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &artifacts))
+	assert.Equal(1, artifacts.Page)
+	assert.Equal(1, artifacts.PerPage)
+	assert.Equal(2, artifacts.TotalPages)
+	assert.Equal("1", artifacts.Items[0].ID)
+}
+
+func TestListArtifacts_Truncated(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var calls int
+	mockArtifactsClient := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			calls++
+			return []buildkite.Artifact{
+					{ID: strconv.Itoa(calls), Path: "log.txt", State: "finished"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: calls + 1,
+				}, nil
+		},
+	}
+
+	_, handler := ListArtifacts(ctx, mockArtifactsClient)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "test-org",
+		"pipeline_slug": "test-pipeline",
+		"build_number":  "123",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var artifacts ArtifactList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &artifacts))
+	assert.Equal(maxArtifactListPages, calls)
+	assert.Equal(maxArtifactListPages, artifacts.Total)
+	assert.True(artifacts.Truncated)
+}
+
 func TestGetArtifact(t *testing.T) {
 	assert := require.New(t)
 
@@ -109,10 +268,10 @@ func TestGetArtifact(t *testing.T) {
 	// Check the structure of the response
 	assert.Contains(textContent.Text, `"status":"200 OK"`)
 	assert.Contains(textContent.Text, `"statusCode":200`)
-	assert.Contains(textContent.Text, `"encoding":"base64"`)
+	assert.Contains(textContent.Text, `"encoding":"utf-8"`)
 
-	// The base64 encoded "This is test artifact content"
-	assert.Contains(textContent.Text, `"data":"VGhpcyBpcyB0ZXN0IGFydGlmYWN0IGNvbnRlbnQ="`)
+	// Text artifacts are returned as is rather than base64 encoded
+	assert.Contains(textContent.Text, `"content":"This is test artifact content"`)
 }
 
 func TestListArtifacts_MissingParameters(t *testing.T) {
@@ -194,5 +353,278 @@ func TestGetArtifact_ErrorResponse(t *testing.T) {
 	result, err := handler(ctx, req)
 	assert.NoError(err)
 	assert.NotNil(result)
-	assert.Contains(getTextResult(t, result).Text, "failed to get artifact")
+	assert.True(result.IsError)
+
+	var apiErr APIError
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &apiErr))
+	assert.Equal("get artifact", apiErr.Operation)
+	assert.Equal(ErrorNotFound, apiErr.Category)
+	assert.Equal("Artifact not found", apiErr.Message)
+}
+
+func TestGetArtifact_TextSelection(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			_, err := writer.Write([]byte("ok 1\nnot ok 2\nok 3\nnot ok 4\nok 5\n"))
+			if err != nil {
+				return nil, err
+			}
+
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+					Status:     "200 OK",
+				},
+			}, nil
+		},
+	}
+
+	_, handler := GetArtifact(ctx, client)
+
+	// Line range only
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"url":        "https://example.com/artifact",
+		"line_start": float64(2),
+		"line_end":   float64(3),
+	}))
+	assert.NoError(err)
+	textContent := getTextResult(t, result)
+	assert.Contains(textContent.Text, `"content":"not ok 2\nok 3"`)
+	assert.Contains(textContent.Text, `"total_lines":5,"line_start":2,"line_end":3`)
+
+	// Grep within a line range
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"url":        "https://example.com/artifact",
+		"line_start": float64(3),
+		"grep":       "^not ok",
+	}))
+	assert.NoError(err)
+	textContent = getTextResult(t, result)
+	assert.Contains(textContent.Text, `"matches":[{"line":4,"text":"not ok 4"}]`)
+	assert.NotContains(textContent.Text, `"content"`)
+
+	// Invalid expressions are reported to the caller
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"url":  "https://example.com/artifact",
+		"grep": "(",
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "invalid grep expression")
+}
+
+func TestGetArtifact_InvalidLineRange(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			_, err := writer.Write([]byte("ok 1\nok 2\n"))
+			if err != nil {
+				return nil, err
+			}
+
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+					Status:     "200 OK",
+				},
+			}, nil
+		},
+	}
+
+	_, handler := GetArtifact(ctx, client)
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		err    string
+	}{
+		{"line_start of 0", map[string]any{"line_start": float64(0)}, "line_start must be at least 1"},
+		{"negative line_start", map[string]any{"line_start": float64(-3)}, "line_start must be at least 1"},
+		{"negative line_end", map[string]any{"line_end": float64(-1)}, "line_end must be at least 1"},
+		{"line_end before line_start", map[string]any{"line_start": float64(5), "line_end": float64(2)}, "line_end must not be before line_start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params["url"] = "https://example.com/artifact"
+			result, err := handler(ctx, createMCPRequest(t, tt.params))
+			assert.NoError(err)
+			assert.True(result.IsError)
+			assert.Equal(tt.err, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func TestApplyTextSelection_Clamps(t *testing.T) {
+	assert := require.New(t)
+
+	var result DecodedContent
+	applyTextSelection(&result, "a\nb\nc\n", -2, -1, nil)
+	assert.Equal(1, result.LineStart)
+	assert.Equal(3, result.LineEnd)
+	assert.Equal("a\nb\nc", result.Content)
+
+	result = DecodedContent{}
+	applyTextSelection(&result, "a\nb\nc\n", 10, 20, nil)
+	assert.Equal(4, result.LineStart)
+	assert.Equal(3, result.LineEnd)
+	assert.Empty(result.Content)
+}
+
+func TestGetArtifact_Image(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	client := &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			_, err := writer.Write(png)
+			if err != nil {
+				return nil, err
+			}
+
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+					Status:     "200 OK",
+				},
+			}, nil
+		},
+	}
+
+	_, handler := GetArtifact(ctx, client)
+
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"url": "https://example.com/screenshot.png",
+	}))
+	assert.NoError(err)
+	assert.Len(result.Content, 2)
+	assert.Contains(getTextResult(t, result).Text, `"mime_type":"image/png"`)
+
+	image, ok := result.Content[1].(mcp.ImageContent)
+	assert.True(ok)
+	assert.Equal("image/png", image.MIMEType)
+	assert.Equal(base64.StdEncoding.EncodeToString(png), image.Data)
+}
+
+func TestGetArtifact_StopsDownloadAtSizeLimit(t *testing.T) {
+	assert := require.New(t)
+
+	var served atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("x"), 32<<10)
+		for range 3200 {
+			n, err := w.Write(chunk)
+			served.Add(int64(n))
+			if err != nil || r.Context().Err() != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	client, err := buildkite.NewOpts(buildkite.WithBaseURL(server.URL))
+	assert.NoError(err)
+
+	ctx := context.Background()
+	_, handler := GetArtifact(ctx, &BuildkiteClientAdapter{Client: client})
+
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"url":      server.URL + "/artifact",
+		"max_size": float64(1024),
+	}))
+	assert.NoError(err)
+	assert.Contains(getTextResult(t, result).Text, `"truncated":true`)
+
+	// the 100MB artifact isn't read to the end just to keep its first 1KB
+	assert.Less(served.Load(), int64(10<<20))
+}
+
+func TestGetArtifact_SizeLimit(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var written int
+	download := func(content []byte) *MockArtifactsClient {
+		written = 0
+		return &MockArtifactsClient{
+			DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+				resp := &buildkite.Response{
+					Response: &http.Response{
+						StatusCode:    200,
+						Status:        "200 OK",
+						ContentLength: int64(len(content)),
+					},
+				}
+
+				// write in chunks, like io.Copy would, stopping at the first write error or cancellation
+				for chunk := range slices.Chunk(content, 4) {
+					if ctx.Err() != nil {
+						return resp, ctx.Err()
+					}
+					n, err := writer.Write(chunk)
+					written += n
+					if err != nil {
+						return resp, err
+					}
+				}
+				return resp, nil
+			},
+		}
+	}
+
+	// Text is truncated to max_size, and the download stops there
+	_, handler := GetArtifact(ctx, download([]byte("line one\nline two\nline three\n")))
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"url":      "https://example.com/artifact",
+		"max_size": float64(10),
+	}))
+	assert.NoError(err)
+	textContent := getTextResult(t, result)
+	assert.Contains(textContent.Text, `"size":29,"truncated":true`)
+	assert.Contains(textContent.Text, `"content":"line one\nl"`)
+	assert.Equal(10, written)
+
+	// Content exactly max_size long isn't truncated
+	_, handler = GetArtifact(ctx, download([]byte("0123456789")))
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"url":      "https://example.com/artifact",
+		"max_size": float64(10),
+	}))
+	assert.NoError(err)
+	assert.Contains(getTextResult(t, result).Text, `"size":10,"encoding"`)
+
+	// Binaries over max_size are refused
+	_, handler = GetArtifact(ctx, download([]byte{0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00}))
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"url":      "https://example.com/artifact.tar.gz",
+		"max_size": float64(8),
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "application/x-gzip binary larger than max_size (8 bytes)")
+}
+
+func TestDownloadArtifact_TooLarge(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var downloads int
+	client := &MockArtifactsClient{
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			downloads++
+			return nil, nil
+		},
+	}
+
+	_, err := downloadArtifact(ctx, client, buildkite.Artifact{Path: "junit.xml", FileSize: 3 << 30})
+	assert.ErrorContains(err, "3221225472 bytes is larger than 10485760 bytes")
+	assert.Zero(downloads)
 }
@@ -0,0 +1,480 @@
+package buildkite
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/pipelineyaml"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultTimelineBuckets is how many slices the build's duration is split into for the parallelism timeline
+const defaultTimelineBuckets = 20
+
+// JobTiming is where a job's time went, in seconds. Offsets are from when the build was created.
+type JobTiming struct {
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	StepKey             string  `json:"step_key,omitempty"`
+	Type                string  `json:"type"`
+	State               string  `json:"state"`
+	Retried             bool    `json:"retried,omitempty"`
+	Agent               string  `json:"agent,omitempty"`
+	QueueWaitSeconds    float64 `json:"queue_wait_seconds"`
+	RunSeconds          float64 `json:"run_seconds"`
+	BlockedSeconds      float64 `json:"blocked_seconds,omitempty"`
+	StartOffsetSeconds  float64 `json:"start_offset_seconds"`
+	FinishOffsetSeconds float64 `json:"finish_offset_seconds"`
+
+	ready, start, end time.Time
+}
+
+// How the predecessors of the jobs on a critical path were found
+const (
+	// CriticalPathStepGraph means every job's predecessor came from the pipeline's step dependencies
+	CriticalPathStepGraph = "step_graph"
+	// CriticalPathTimestamps means every job's predecessor was taken to be the job which finished last
+	// before it became runnable, as none of the jobs' steps were in the pipeline's step graph
+	CriticalPathTimestamps = "timestamps"
+	// CriticalPathMixed means the step graph was followed where it covered the jobs, and timestamps
+	// used for the rest, such as steps added by pipeline upload
+	CriticalPathMixed = "mixed"
+)
+
+// CriticalPath is the chain of jobs which determined when the build finished
+type CriticalPath struct {
+	Source           string      `json:"source,omitempty"`
+	Jobs             []JobTiming `json:"jobs"`
+	QueueWaitSeconds float64     `json:"queue_wait_seconds"`
+	RunSeconds       float64     `json:"run_seconds"`
+	BlockedSeconds   float64     `json:"blocked_seconds"`
+}
+
+// TimelineBucket is the average number of jobs running and waiting for an agent during a slice of the build
+type TimelineBucket struct {
+	StartOffsetSeconds float64 `json:"start_offset_seconds"`
+	Running            float64 `json:"running"`
+	Waiting            float64 `json:"waiting"`
+}
+
+// BuildTiming breaks down how long a build took
+type BuildTiming struct {
+	BuildNumber        int                                  `json:"build_number"`
+	State              string                               `json:"state"`
+	DurationSeconds    float64                              `json:"duration_seconds"`
+	QueueWaitSeconds   float64                              `json:"queue_wait_seconds"`
+	RunSeconds         float64                              `json:"run_seconds"`
+	BlockedSeconds     float64                              `json:"blocked_seconds"`
+	MaxParallelism     int                                  `json:"max_parallelism"`
+	AverageParallelism float64                              `json:"average_parallelism"`
+	CriticalPath       CriticalPath                         `json:"critical_path"`
+	Timeline           []TimelineBucket                     `json:"timeline"`
+	Jobs               ClientSidePaginatedResult[JobTiming] `json:"jobs"`
+}
+
+func AnalyzeBuildTiming(ctx context.Context, client BuildsClient, pipelines PipelinesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("analyze_build_timing",
+			mcp.WithDescription("Explain where the time went in a finished build: how long each job waited for an agent versus ran (block steps report how long they were blocked), the critical path of jobs which determined when the build finished, and how many jobs were running and waiting over the course of the build. The critical path follows the step dependencies in the pipeline's configuration (wait and block steps, depends_on and groups), matching jobs to steps by key, or by label for steps without a key. For jobs whose steps aren't in the configuration, such as those added by pipeline upload, it falls back to taking the job which finished last before the job became runnable. Jobs are ordered by total time, longest first"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The number of the build"),
+			),
+			mcp.WithNumber("timeline_buckets",
+				mcp.Description("Number of slices to split the build's duration into for the timeline (default 20)"),
+				mcp.Min(1),
+				mcp.Max(100),
+			),
+			withClientSidePagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Analyze Build Timing",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.AnalyzeBuildTiming")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buckets := request.GetInt("timeline_buckets", defaultTimelineBuckets)
+			if buckets < 1 {
+				buckets = defaultTimelineBuckets
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.Int("timeline_buckets", buckets),
+			)
+
+			build, resp, err := client.Get(ctx, org, pipelineSlug, buildNumber, &buildkite.BuildGetOptions{})
+			if err != nil {
+				return apiErrorResult("get build", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get build", resp, nil), nil
+			}
+
+			if build.CreatedAt == nil || build.FinishedAt == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("build %d has not finished yet, it is %s", build.Number, build.State)), nil
+			}
+
+			graph := pipelineStepGraph(ctx, pipelines, org, pipelineSlug)
+
+			timing := analyzeBuildTiming(build, graph, buckets)
+
+			slices.SortStableFunc(timing.Jobs.Items, func(a, b JobTiming) int {
+				return cmp.Compare(b.QueueWaitSeconds+b.RunSeconds+b.BlockedSeconds, a.QueueWaitSeconds+a.RunSeconds+a.BlockedSeconds)
+			})
+			timing.Jobs = applyClientSidePagination(timing.Jobs.Items, getClientSidePaginationParams(request))
+
+			span.SetAttributes(
+				attribute.Int("jobs", timing.Jobs.Total),
+				attribute.Int("critical_path_jobs", len(timing.CriticalPath.Jobs)),
+				attribute.String("critical_path_source", timing.CriticalPath.Source),
+			)
+
+			r, err := json.Marshal(&timing)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal build timing: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// pipelineStepGraph builds the step graph of a pipeline's configuration, or returns nil if the
+// pipeline can't be read or has no valid YAML steps
+func pipelineStepGraph(ctx context.Context, client PipelinesClient, org, pipelineSlug string) *pipelineyaml.Graph {
+	pipeline, resp, err := client.Get(ctx, org, pipelineSlug)
+	if err != nil || resp.StatusCode != http.StatusOK || strings.TrimSpace(pipeline.Configuration) == "" {
+		return nil
+	}
+
+	steps, _ := pipelineyaml.Parse([]byte(pipeline.Configuration))
+	if steps == nil {
+		return nil
+	}
+
+	graph, err := pipelineyaml.BuildGraph(steps)
+	if err != nil {
+		return nil
+	}
+	return graph
+}
+
+// analyzeBuildTiming works out the timings of a finished build's jobs, leaving them unpaginated and in build order.
+// The step graph is optional.
+func analyzeBuildTiming(build buildkite.Build, graph *pipelineyaml.Graph, buckets int) BuildTiming {
+	origin, finished := build.CreatedAt.Time, build.FinishedAt.Time
+
+	timing := BuildTiming{
+		BuildNumber:     build.Number,
+		State:           build.State,
+		DurationSeconds: seconds(finished.Sub(origin)),
+		Timeline:        make([]TimelineBucket, 0, buckets),
+	}
+
+	jobs := make([]JobTiming, 0, len(build.Jobs))
+	for _, job := range build.Jobs {
+		jobTiming, ok := timeJob(job, origin)
+		if !ok {
+			continue
+		}
+		jobs = append(jobs, jobTiming)
+
+		timing.QueueWaitSeconds += jobTiming.QueueWaitSeconds
+		timing.RunSeconds += jobTiming.RunSeconds
+		timing.BlockedSeconds += jobTiming.BlockedSeconds
+	}
+	timing.QueueWaitSeconds = round(timing.QueueWaitSeconds, 1)
+	timing.RunSeconds = round(timing.RunSeconds, 1)
+	timing.BlockedSeconds = round(timing.BlockedSeconds, 1)
+
+	timing.CriticalPath = criticalPath(jobs, graph)
+	timing.MaxParallelism = maxParallelism(jobs)
+	if timing.DurationSeconds > 0 {
+		timing.AverageParallelism = round(timing.RunSeconds/timing.DurationSeconds, 2)
+	}
+
+	width := finished.Sub(origin) / time.Duration(buckets)
+	for i := 0; i < buckets && width > 0; i++ {
+		from := origin.Add(time.Duration(i) * width)
+		to := from.Add(width)
+
+		var running, waiting time.Duration
+		for _, job := range jobs {
+			if job.Type == "manual" {
+				continue
+			}
+			running += overlap(job.start, job.end, from, to)
+			waiting += overlap(job.ready, job.start, from, to)
+		}
+
+		timing.Timeline = append(timing.Timeline, TimelineBucket{
+			StartOffsetSeconds: seconds(from.Sub(origin)),
+			Running:            round(running.Seconds()/width.Seconds(), 2),
+			Waiting:            round(waiting.Seconds()/width.Seconds(), 2),
+		})
+	}
+
+	timing.Jobs = ClientSidePaginatedResult[JobTiming]{Items: jobs}
+	return timing
+}
+
+// timeJob reads when a job became runnable, started and finished. Block steps finish when they're
+// unblocked, and wait steps and jobs which never ran are skipped.
+func timeJob(job buildkite.Job, origin time.Time) (JobTiming, bool) {
+	timing := JobTiming{
+		ID:      job.ID,
+		Name:    job.Label,
+		StepKey: job.StepKey,
+		Type:    job.Type,
+		State:   job.State,
+		Retried: job.Retried,
+		Agent:   job.Agent.Name,
+	}
+	if timing.Name == "" {
+		timing.Name = job.Name
+	}
+
+	switch {
+	case job.RunnableAt != nil:
+		timing.ready = job.RunnableAt.Time
+	case job.ScheduledAt != nil:
+		timing.ready = job.ScheduledAt.Time
+	case job.CreatedAt != nil:
+		timing.ready = job.CreatedAt.Time
+	default:
+		return timing, false
+	}
+
+	switch job.Type {
+	case "waiter":
+		return timing, false
+	case "manual":
+		if job.UnblockedAt == nil {
+			return timing, false
+		}
+		timing.start, timing.end = timing.ready, job.UnblockedAt.Time
+		timing.BlockedSeconds = seconds(timing.end.Sub(timing.start))
+	default:
+		if job.StartedAt == nil || job.FinishedAt == nil {
+			return timing, false
+		}
+		timing.start, timing.end = job.StartedAt.Time, job.FinishedAt.Time
+		timing.QueueWaitSeconds = seconds(max(timing.start.Sub(timing.ready), 0))
+		timing.RunSeconds = seconds(timing.end.Sub(timing.start))
+	}
+
+	timing.StartOffsetSeconds = seconds(timing.start.Sub(origin))
+	timing.FinishOffsetSeconds = seconds(timing.end.Sub(origin))
+	return timing, true
+}
+
+// criticalPath walks back from the job which finished last to the dependency it was waiting for,
+// each time the job of the step it depends on which finished last. Jobs whose steps aren't in the
+// step graph, or when there's no graph, are taken to be waiting for the job which finished last
+// before they became runnable.
+func criticalPath(jobs []JobTiming, graph *pipelineyaml.Graph) CriticalPath {
+	path := CriticalPath{Jobs: make([]JobTiming, 0)}
+	jobSteps, stepJobs := matchJobSteps(jobs, graph)
+
+	current := latestFinished(jobs, func(i int) bool { return true })
+
+	var fromGraph, fromTimestamps bool
+	visited := make(map[int]bool)
+	for current != -1 && !visited[current] {
+		visited[current] = true
+		job := jobs[current]
+		path.Jobs = append(path.Jobs, job)
+		path.QueueWaitSeconds += job.QueueWaitSeconds
+		path.RunSeconds += job.RunSeconds
+		path.BlockedSeconds += job.BlockedSeconds
+
+		if step, ok := jobSteps[current]; ok {
+			deps := graph.Dependencies(step)
+
+			candidates := make(map[int]bool)
+			for _, dep := range deps {
+				for _, i := range stepJobs[dep] {
+					candidates[i] = true
+				}
+			}
+
+			// the job's step depends on nothing, or on steps whose jobs can be found
+			if len(deps) == 0 || len(candidates) > 0 {
+				fromGraph = true
+				current = latestFinished(jobs, func(i int) bool {
+					return candidates[i] && !visited[i]
+				})
+				continue
+			}
+		}
+
+		// timestamps are recorded to the second, so allow for a job becoming runnable a moment early
+		fromTimestamps = true
+		ready := job.ready.Add(time.Second)
+		current = latestFinished(jobs, func(i int) bool {
+			return !visited[i] && !jobs[i].end.After(ready)
+		})
+	}
+
+	switch {
+	case fromGraph && fromTimestamps:
+		path.Source = CriticalPathMixed
+	case fromGraph:
+		path.Source = CriticalPathStepGraph
+	case fromTimestamps:
+		path.Source = CriticalPathTimestamps
+	}
+
+	slices.Reverse(path.Jobs)
+	path.QueueWaitSeconds = round(path.QueueWaitSeconds, 1)
+	path.RunSeconds = round(path.RunSeconds, 1)
+	path.BlockedSeconds = round(path.BlockedSeconds, 1)
+	return path
+}
+
+// matchJobSteps finds the step in the graph each job ran, by the step's key or, for steps without a
+// key, by a label no other step has. It returns the step of each job and the jobs of each step.
+func matchJobSteps(jobs []JobTiming, graph *pipelineyaml.Graph) (map[int]string, map[string][]int) {
+	jobSteps := make(map[int]string)
+	stepJobs := make(map[string][]int)
+	if graph == nil {
+		return jobSteps, stepJobs
+	}
+
+	keys := make(map[string]bool)
+	labels := make(map[string]string)
+	for _, node := range graph.Nodes {
+		if node.Type == pipelineyaml.TypeWait || node.Type == pipelineyaml.TypeGroup {
+			continue
+		}
+		keys[node.ID] = true
+		if node.Label == "" {
+			continue
+		}
+		if _, ok := labels[node.Label]; ok {
+			labels[node.Label] = ""
+		} else {
+			labels[node.Label] = node.ID
+		}
+	}
+
+	for i, job := range jobs {
+		var step string
+		switch {
+		case job.StepKey != "":
+			if keys[job.StepKey] {
+				step = job.StepKey
+			}
+		default:
+			step = labels[job.Name]
+		}
+		if step == "" {
+			continue
+		}
+		jobSteps[i] = step
+		stepJobs[step] = append(stepJobs[step], i)
+	}
+	return jobSteps, stepJobs
+}
+
+// latestFinished is the index of the job which finished last of those include accepts, or -1 if
+// there are none
+func latestFinished(jobs []JobTiming, include func(i int) bool) int {
+	latest := -1
+	for i, job := range jobs {
+		if !include(i) {
+			continue
+		}
+		if latest == -1 || job.end.After(jobs[latest].end) {
+			latest = i
+		}
+	}
+	return latest
+}
+
+// maxParallelism is the most jobs running on agents at once
+func maxParallelism(jobs []JobTiming) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+
+	var events []event
+	for _, job := range jobs {
+		if job.Type == "manual" {
+			continue
+		}
+		events = append(events, event{job.start, 1}, event{job.end, -1})
+	}
+
+	// jobs finishing at the same moment another starts don't overlap
+	slices.SortFunc(events, func(a, b event) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		return a.delta - b.delta
+	})
+
+	running, most := 0, 0
+	for _, e := range events {
+		running += e.delta
+		most = max(most, running)
+	}
+	return most
+}
+
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return max(end.Sub(start), 0)
+}
+
+func seconds(d time.Duration) float64 {
+	return round(d.Seconds(), 1)
+}
+
+func round(f float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(f*scale) / scale
+}
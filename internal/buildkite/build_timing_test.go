@@ -0,0 +1,243 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/pipelineyaml"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeBuildTiming(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	origin := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: origin.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	client := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			return buildkite.Build{
+					Number:     42,
+					State:      "passed",
+					CreatedAt:  at(0),
+					FinishedAt: at(400),
+					Jobs: []buildkite.Job{
+						{ID: "lint", Type: "script", Label: "Lint", RunnableAt: at(0), StartedAt: at(10), FinishedAt: at(70)},
+						{ID: "test", Type: "script", Label: "Test", RunnableAt: at(0), StartedAt: at(5), FinishedAt: at(305)},
+						{ID: "wait", Type: "waiter"},
+						{ID: "deploy", Type: "script", Label: "Deploy", RunnableAt: at(306), StartedAt: at(330), FinishedAt: at(400)},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	// without a step graph the critical path is worked out from timestamps
+	pipelines := &MockPipelinesClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
+			return buildkite.Pipeline{}, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 404,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := AnalyzeBuildTiming(ctx, client, pipelines)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":              "org",
+		"pipeline_slug":    "pipeline",
+		"build_number":     "42",
+		"timeline_buckets": float64(4),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var timing BuildTiming
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &timing))
+	assert.Equal(400.0, timing.DurationSeconds)
+	assert.Equal(39.0, timing.QueueWaitSeconds)
+	assert.Equal(430.0, timing.RunSeconds)
+	assert.Equal(2, timing.MaxParallelism)
+	assert.Equal(1.08, timing.AverageParallelism)
+
+	assert.Equal(CriticalPathTimestamps, timing.CriticalPath.Source)
+	assert.Len(timing.CriticalPath.Jobs, 2)
+	assert.Equal("test", timing.CriticalPath.Jobs[0].ID)
+	assert.Equal("deploy", timing.CriticalPath.Jobs[1].ID)
+	assert.Equal(29.0, timing.CriticalPath.QueueWaitSeconds)
+	assert.Equal(370.0, timing.CriticalPath.RunSeconds)
+
+	assert.Equal([]TimelineBucket{
+		{StartOffsetSeconds: 0, Running: 1.55, Waiting: 0.15},
+		{StartOffsetSeconds: 100, Running: 1, Waiting: 0},
+		{StartOffsetSeconds: 200, Running: 1, Waiting: 0},
+		{StartOffsetSeconds: 300, Running: 0.75, Waiting: 0.24},
+	}, timing.Timeline)
+
+	assert.Equal(3, timing.Jobs.Total)
+	assert.Equal("test", timing.Jobs.Items[0].ID)
+	assert.Equal("deploy", timing.Jobs.Items[1].ID)
+	assert.Equal(24.0, timing.Jobs.Items[1].QueueWaitSeconds)
+	assert.Equal(330.0, timing.Jobs.Items[1].StartOffsetSeconds)
+}
+
+func TestAnalyzeBuildTiming_Running(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			return buildkite.Build{
+					Number:    42,
+					State:     "running",
+					CreatedAt: &buildkite.Timestamp{Time: time.Now()},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := AnalyzeBuildTiming(ctx, client, &MockPipelinesClient{})
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "42",
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "has not finished yet")
+}
+
+func TestAnalyzeBuildTiming_StepGraph(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	origin := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: origin.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	// lint finishes after build, just before test becomes runnable, so the timestamps alone would
+	// put it on the critical path even though test only depends on build
+	client := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			return buildkite.Build{
+					Number:     42,
+					State:      "passed",
+					CreatedAt:  at(0),
+					FinishedAt: at(500),
+					Jobs: []buildkite.Job{
+						{ID: "build", Type: "script", Label: "Build", StepKey: "build", RunnableAt: at(0), StartedAt: at(0), FinishedAt: at(90)},
+						{ID: "lint", Type: "script", Label: "Lint", StepKey: "lint", RunnableAt: at(0), StartedAt: at(0), FinishedAt: at(100)},
+						{ID: "test", Type: "script", Label: "Test", StepKey: "test", RunnableAt: at(100), StartedAt: at(100), FinishedAt: at(400)},
+						{ID: "wait", Type: "waiter"},
+						{ID: "notify", Type: "script", Label: "Notify", RunnableAt: at(400), StartedAt: at(410), FinishedAt: at(420)},
+						{ID: "uploaded", Type: "script", Label: "Uploaded", RunnableAt: at(420), StartedAt: at(420), FinishedAt: at(500)},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	pipelines := &MockPipelinesClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
+			return buildkite.Pipeline{
+					Configuration: `steps:
+  - command: make build
+    key: build
+  - command: make lint
+    key: lint
+  - command: make test
+    key: test
+    depends_on: build
+  - wait
+  - label: Notify
+    command: notify
+`,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := AnalyzeBuildTiming(ctx, client, pipelines)
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "42",
+	}))
+	assert.NoError(err)
+
+	var timing BuildTiming
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &timing))
+
+	// uploaded isn't in the configuration so its predecessor comes from timestamps, then notify is
+	// matched by label and waits for every step before the wait
+	assert.Equal(CriticalPathMixed, timing.CriticalPath.Source)
+	ids := make([]string, 0, len(timing.CriticalPath.Jobs))
+	for _, job := range timing.CriticalPath.Jobs {
+		ids = append(ids, job.ID)
+	}
+	assert.Equal([]string{"build", "test", "notify", "uploaded"}, ids)
+}
+
+func TestCriticalPath_StepGraphOnly(t *testing.T) {
+	assert := require.New(t)
+
+	origin := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	job := func(id string, ready, start, end int) JobTiming {
+		return JobTiming{
+			ID:      id,
+			StepKey: id,
+			ready:   origin.Add(time.Duration(ready) * time.Second),
+			start:   origin.Add(time.Duration(start) * time.Second),
+			end:     origin.Add(time.Duration(end) * time.Second),
+		}
+	}
+
+	pipeline, problems := pipelineyaml.Parse([]byte(`steps:
+  - command: a
+    key: a
+  - command: b
+    key: b
+  - command: c
+    key: c
+    depends_on: a
+`))
+	assert.Empty(problems)
+	graph, err := pipelineyaml.BuildGraph(pipeline)
+	assert.NoError(err)
+
+	// b finishes closest to c becoming runnable, but c depends on a
+	path := criticalPath([]JobTiming{job("a", 0, 0, 50), job("b", 0, 0, 60), job("c", 60, 60, 100)}, graph)
+	assert.Equal(CriticalPathStepGraph, path.Source)
+	assert.Equal("a", path.Jobs[0].ID)
+	assert.Equal("c", path.Jobs[1].ID)
+
+	path = criticalPath([]JobTiming{job("a", 0, 0, 50), job("b", 0, 0, 60), job("c", 60, 60, 100)}, nil)
+	assert.Equal(CriticalPathTimestamps, path.Source)
+	assert.Equal("b", path.Jobs[0].ID)
+}
@@ -68,10 +68,11 @@ func withClientSidePagination() mcp.ToolOption {
 }
 
 // getClientSidePaginationParams extracts client-side pagination parameters from request
-// Always returns pagination params with sensible defaults
+// Always returns pagination params with sensible defaults, raising values below 1 to 1 as
+// applyClientSidePagination can't page by them
 func getClientSidePaginationParams(r mcp.CallToolRequest) ClientSidePaginationParams {
-	page := r.GetInt("page", 1)
-	perPage := r.GetInt("perPage", 25) // Default page size for client-side pagination
+	page := max(r.GetInt("page", 1), 1)
+	perPage := max(r.GetInt("perPage", 25), 1) // Default page size for client-side pagination
 	
 	return ClientSidePaginationParams{
 		Page:    page,
@@ -103,6 +103,17 @@ func Test_getClientSidePaginationParams(t *testing.T) {
 				PerPage: 25, // default
 			},
 		},
+		{
+			name: "values below 1",
+			args: map[string]any{
+				"page":    float64(0),
+				"perPage": float64(-5),
+			},
+			expectedParams: ClientSidePaginationParams{
+				Page:    1,
+				PerPage: 1,
+			},
+		},
 	}
 
 	for _, tt := range tests {
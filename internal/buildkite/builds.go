@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
@@ -17,6 +16,7 @@ import (
 type BuildsClient interface {
 	Get(ctx context.Context, org, pipelineSlug, buildNumber string, options *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error)
 	ListByPipeline(ctx context.Context, org, pipelineSlug string, options *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error)
+	ListByOrg(ctx context.Context, org string, options *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error)
 }
 
 // JobSummary represents a summary of jobs grouped by state, with finished jobs classified as passed/failed
@@ -91,15 +91,11 @@ func ListBuilds(ctx context.Context, client BuildsClient) (tool mcp.Tool, handle
 
 			builds, resp, err := client.ListByPipeline(ctx, org, pipelineSlug, options)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list builds", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
+				return apiErrorResult("list builds", resp, nil), nil
 			}
 
 			result := PaginatedResult[buildkite.Build]{
@@ -167,15 +163,11 @@ func GetBuildTestEngineRuns(ctx context.Context, client BuildsClient) (tool mcp.
 				IncludeTestEngine: true,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get build", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get build: %s", string(body))), nil
+				return apiErrorResult("get build", resp, nil), nil
 			}
 
 			// Extract just the test engine runs data
@@ -242,15 +234,11 @@ func GetBuild(ctx context.Context, client BuildsClient) (tool mcp.Tool, handler
 				IncludeTestEngine: true,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get build", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get build: %s", string(body))), nil
+				return apiErrorResult("get build", resp, nil), nil
 			}
 
 			// Create job summary
@@ -282,3 +270,31 @@ func GetBuild(ctx context.Context, client BuildsClient) (tool mcp.Tool, handler
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// listBuildsUpTo follows the pages of a pipeline's builds until there are no more or limit builds
+// have been read, reporting whether builds were left unread
+func listBuildsUpTo(ctx context.Context, client BuildsClient, org, pipelineSlug string, options *buildkite.BuildsListOptions, limit int) ([]buildkite.Build, bool, error) {
+	var all []buildkite.Build
+
+	options.PerPage = min(100, limit)
+	for {
+		builds, resp, err := client.ListByPipeline(ctx, org, pipelineSlug, options)
+		if err != nil {
+			return nil, false, newAPIError("list builds", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, false, newAPIError("list builds", resp, nil)
+		}
+
+		all = append(all, builds...)
+		if len(all) >= limit {
+			return all[:limit], len(all) > limit || resp.NextPage != 0, nil
+		}
+
+		if resp.NextPage == 0 {
+			return all, false, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
@@ -13,6 +13,7 @@ import (
 type MockBuildsClient struct {
 	ListByPipelineFunc func(ctx context.Context, org string, pipeline string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error)
 	GetFunc            func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error)
+	ListByOrgFunc      func(ctx context.Context, org string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error)
 }
 
 func (m *MockBuildsClient) Get(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
@@ -29,6 +30,13 @@ func (m *MockBuildsClient) ListByPipeline(ctx context.Context, org string, pipel
 	return nil, nil, nil
 }
 
+func (m *MockBuildsClient) ListByOrg(ctx context.Context, org string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error) {
+	if m.ListByOrgFunc != nil {
+		return m.ListByOrgFunc(ctx, org, opt)
+	}
+	return nil, nil, nil
+}
+
 var _ BuildsClient = (*MockBuildsClient)(nil)
 
 func TestGetBuildDefault(t *testing.T) {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -15,6 +17,117 @@ import (
 type ClusterQueuesClient interface {
 	List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error)
 	Get(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error)
+	Pause(ctx context.Context, org, clusterID, queueID string, qp buildkite.ClusterQueuePause) (buildkite.ClusterQueue, *buildkite.Response, error)
+	Resume(ctx context.Context, org, clusterID, queueID string) (*buildkite.Response, error)
+	Create(ctx context.Context, org, clusterID string, qc ClusterQueueCreate) (HostedClusterQueue, *buildkite.Response, error)
+	Update(ctx context.Context, org, clusterID, queueID string, qu ClusterQueueUpdate) (HostedClusterQueue, *buildkite.Response, error)
+}
+
+// Retry agent affinities, which decide whether a retried job prefers the agent it last ran on
+const (
+	RetryAgentAffinityPreferWarmest   = "prefer-warmest"
+	RetryAgentAffinityPreferDifferent = "prefer-different"
+)
+
+// HostedAgents configures the Buildkite hosted agents which run a queue's jobs
+type HostedAgents struct {
+	InstanceShape string             `json:"instance_shape,omitempty"`
+	Linux         *HostedAgentsLinux `json:"linux,omitempty"`
+	Mac           *HostedAgentsMacOS `json:"mac,omitempty"`
+}
+
+// HostedAgentsLinux configures Linux hosted agents
+type HostedAgentsLinux struct {
+	AgentImageRef string `json:"agent_image_ref,omitempty"`
+}
+
+// HostedAgentsMacOS configures macOS hosted agents
+type HostedAgentsMacOS struct {
+	XcodeVersion string `json:"xcode_version,omitempty"`
+}
+
+// ClusterQueueCreate creates a queue, which is self-hosted unless HostedAgents is set
+type ClusterQueueCreate struct {
+	Key                string        `json:"key"`
+	Description        string        `json:"description,omitempty"`
+	RetryAgentAffinity string        `json:"retry_agent_affinity,omitempty"`
+	HostedAgents       *HostedAgents `json:"hosted_agents,omitempty"`
+}
+
+// ClusterQueueUpdate changes the fields of a queue which are set
+type ClusterQueueUpdate struct {
+	Description        string        `json:"description,omitempty"`
+	RetryAgentAffinity string        `json:"retry_agent_affinity,omitempty"`
+	HostedAgents       *HostedAgents `json:"hosted_agents,omitempty"`
+}
+
+// HostedClusterQueue is a queue along with the hosted agent settings go-buildkite doesn't decode. The
+// hosted agent configuration is passed through as the API returns it.
+type HostedClusterQueue struct {
+	buildkite.ClusterQueue
+	Hosted             bool            `json:"hosted"`
+	HostedAgents       json.RawMessage `json:"hosted_agents,omitempty"`
+	RetryAgentAffinity string          `json:"retry_agent_affinity,omitempty"`
+}
+
+// ClusterQueuesClientAdapter adds creating and updating queues with hosted agent settings, which
+// go-buildkite doesn't support, to the cluster queues service
+type ClusterQueuesClientAdapter struct {
+	*buildkite.Client
+}
+
+// List implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error) {
+	return a.ClusterQueues.List(ctx, org, clusterID, opts)
+}
+
+// Get implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) Get(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error) {
+	return a.ClusterQueues.Get(ctx, org, clusterID, queueID)
+}
+
+// Pause implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) Pause(ctx context.Context, org, clusterID, queueID string, qp buildkite.ClusterQueuePause) (buildkite.ClusterQueue, *buildkite.Response, error) {
+	return a.ClusterQueues.Pause(ctx, org, clusterID, queueID, qp)
+}
+
+// Resume implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) Resume(ctx context.Context, org, clusterID, queueID string) (*buildkite.Response, error) {
+	return a.ClusterQueues.Resume(ctx, org, clusterID, queueID)
+}
+
+// Create implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) Create(ctx context.Context, org, clusterID string, qc ClusterQueueCreate) (HostedClusterQueue, *buildkite.Response, error) {
+	u := fmt.Sprintf("v2/organizations/%s/clusters/%s/queues", org, clusterID)
+	req, err := a.NewRequest(ctx, http.MethodPost, u, qc)
+	if err != nil {
+		return HostedClusterQueue{}, nil, err
+	}
+
+	var queue HostedClusterQueue
+	resp, err := a.Do(req, &queue)
+	if err != nil {
+		return HostedClusterQueue{}, resp, err
+	}
+
+	return queue, resp, nil
+}
+
+// Update implements ClusterQueuesClient
+func (a *ClusterQueuesClientAdapter) Update(ctx context.Context, org, clusterID, queueID string, qu ClusterQueueUpdate) (HostedClusterQueue, *buildkite.Response, error) {
+	u := fmt.Sprintf("v2/organizations/%s/clusters/%s/queues/%s", org, clusterID, queueID)
+	req, err := a.NewRequest(ctx, http.MethodPatch, u, qu)
+	if err != nil {
+		return HostedClusterQueue{}, nil, err
+	}
+
+	var queue HostedClusterQueue
+	resp, err := a.Do(req, &queue)
+	if err != nil {
+		return HostedClusterQueue{}, resp, err
+	}
+
+	return queue, resp, nil
 }
 
 func ListClusterQueues(ctx context.Context, client ClusterQueuesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
@@ -62,11 +175,11 @@ func ListClusterQueues(ctx context.Context, client ClusterQueuesClient) (tool mc
 				ListOptions: paginationParams,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list cluster queues", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("Failed to list clusters"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list cluster queues", resp, nil), nil
 			}
 			if len(queues) == 0 {
 				return mcp.NewToolResultText("No clusters found"), nil
@@ -133,11 +246,11 @@ func GetClusterQueue(ctx context.Context, client ClusterQueuesClient) (tool mcp.
 
 			queue, resp, err := client.Get(ctx, org, clusterID, queueID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get cluster queue", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("Failed to list clusters"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get cluster queue", resp, nil), nil
 			}
 
 			r, err := json.Marshal(queue)
@@ -148,3 +261,443 @@ func GetClusterQueue(ctx context.Context, client ClusterQueuesClient) (tool mcp.
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// QueueDispatchState is whether a queue is dispatching jobs to agents
+type QueueDispatchState struct {
+	Paused   bool                      `json:"paused"`
+	PausedBy *buildkite.ClusterCreator `json:"paused_by,omitempty"`
+	PausedAt *buildkite.Timestamp      `json:"paused_at,omitempty"`
+	Note     string                    `json:"note,omitempty"`
+}
+
+// QueueDispatchChange is a queue's dispatch state before and after pausing or resuming it. Changed is
+// false when the queue was already in the requested state, in which case it was left alone.
+type QueueDispatchChange struct {
+	QueueID string             `json:"queue_id"`
+	Key     string             `json:"key,omitempty"`
+	Changed bool               `json:"changed"`
+	Before  QueueDispatchState `json:"before"`
+	After   QueueDispatchState `json:"after"`
+}
+
+func PauseQueueDispatch(ctx context.Context, client ClusterQueuesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("pause_queue_dispatch",
+			mcp.WithDescription("Pause dispatch on a cluster queue so no new jobs are assigned to its agents, for example to mitigate a misbehaving queue during an incident. Jobs already running carry on. Returns the queue's dispatch state before and after"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("queue_id",
+				mcp.Required(),
+				mcp.Description("The id of the queue"),
+			),
+			mcp.WithString("reason",
+				mcp.Required(),
+				mcp.Description("Why dispatch is being paused, which is shown on the queue until it's resumed"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Pause Queue Dispatch",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.PauseQueueDispatch")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			queueID, err := request.RequireString("queue_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			reason, err := request.RequireString("reason")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if strings.TrimSpace(reason) == "" {
+				return mcp.NewToolResultError("reason must not be empty"), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.String("queue_id", queueID),
+			)
+
+			queue, err := getClusterQueue(ctx, client, org, clusterID, queueID)
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			change := QueueDispatchChange{
+				QueueID: queue.ID,
+				Key:     queue.Key,
+				Before:  queueDispatchState(queue),
+				After:   queueDispatchState(queue),
+			}
+
+			if !queue.DispatchPaused {
+				paused, resp, err := client.Pause(ctx, org, clusterID, queueID, buildkite.ClusterQueuePause{Note: reason})
+				if err != nil {
+					return apiErrorResult("pause queue dispatch", resp, err), nil
+				}
+
+				if resp.StatusCode != http.StatusOK {
+					return apiErrorResult("pause queue dispatch", resp, nil), nil
+				}
+
+				change.Changed = true
+				change.After = queueDispatchState(paused)
+			}
+
+			span.SetAttributes(attribute.Bool("changed", change.Changed))
+
+			r, err := json.Marshal(&change)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal queue dispatch change: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func ResumeQueueDispatch(ctx context.Context, client ClusterQueuesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("resume_queue_dispatch",
+			mcp.WithDescription("Resume dispatch on a paused cluster queue so jobs are assigned to its agents again. Returns the queue's dispatch state before and after"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("queue_id",
+				mcp.Required(),
+				mcp.Description("The id of the queue"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Resume Queue Dispatch",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ResumeQueueDispatch")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			queueID, err := request.RequireString("queue_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.String("queue_id", queueID),
+			)
+
+			queue, err := getClusterQueue(ctx, client, org, clusterID, queueID)
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			change := QueueDispatchChange{
+				QueueID: queue.ID,
+				Key:     queue.Key,
+				Before:  queueDispatchState(queue),
+				After:   queueDispatchState(queue),
+			}
+
+			if queue.DispatchPaused {
+				resp, err := client.Resume(ctx, org, clusterID, queueID)
+				if err != nil {
+					return apiErrorResult("resume queue dispatch", resp, err), nil
+				}
+
+				if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+					return apiErrorResult("resume queue dispatch", resp, nil), nil
+				}
+
+				// resuming doesn't return the queue, so read it back
+				resumed, err := getClusterQueue(ctx, client, org, clusterID, queueID)
+				if err != nil {
+					return toolErrorResult(err), nil
+				}
+
+				change.Changed = true
+				change.After = queueDispatchState(resumed)
+			}
+
+			span.SetAttributes(attribute.Bool("changed", change.Changed))
+
+			r, err := json.Marshal(&change)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal queue dispatch change: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func getClusterQueue(ctx context.Context, client ClusterQueuesClient, org, clusterID, queueID string) (buildkite.ClusterQueue, error) {
+	queue, resp, err := client.Get(ctx, org, clusterID, queueID)
+	if err != nil {
+		return buildkite.ClusterQueue{}, newAPIError("get cluster queue", resp, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return buildkite.ClusterQueue{}, newAPIError("get cluster queue", resp, nil)
+	}
+
+	return queue, nil
+}
+
+func queueDispatchState(queue buildkite.ClusterQueue) QueueDispatchState {
+	return QueueDispatchState{
+		Paused:   queue.DispatchPaused,
+		PausedBy: queue.DispatchPausedBy,
+		PausedAt: queue.DispatchPausedAt,
+		Note:     queue.DispatchPausedNote,
+	}
+}
+
+func CreateClusterQueue(ctx context.Context, client ClusterQueuesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_cluster_queue",
+			mcp.WithDescription("Create a queue in a cluster. The queue is self-hosted, with jobs run by agents started with its key as their queue tag, unless a hosted agents instance shape is given, in which case Buildkite runs the agents"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("key",
+				mcp.Required(),
+				mcp.Description("The key of the queue, which steps target with agents: {queue: <key>}"),
+			),
+			mcp.WithString("description",
+				mcp.Description("A description of the queue"),
+			),
+			withQueueAgentParams(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Create Cluster Queue",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				IdempotentHint:  mcp.ToBoolPtr(false),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.CreateClusterQueue")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			key, err := request.RequireString("key")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			hostedAgents, err := hostedAgentsParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if hostedAgents != nil && hostedAgents.InstanceShape == "" {
+				return mcp.NewToolResultError("hosted_agents_instance_shape is required to configure hosted agents"), nil
+			}
+
+			create := ClusterQueueCreate{
+				Key:                key,
+				Description:        request.GetString("description", ""),
+				RetryAgentAffinity: request.GetString("retry_agent_affinity", ""),
+				HostedAgents:       hostedAgents,
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.String("key", key),
+				attribute.Bool("hosted", hostedAgents != nil),
+			)
+
+			queue, resp, err := client.Create(ctx, org, clusterID, create)
+			if err != nil {
+				return apiErrorResult("create cluster queue", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusCreated {
+				return apiErrorResult("create cluster queue", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&queue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster queue response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func UpdateClusterQueue(ctx context.Context, client ClusterQueuesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_cluster_queue",
+			mcp.WithDescription("Update the settings of an existing cluster queue, including the instance shape, agent image or Xcode version of a hosted queue. Only the fields provided are changed, and a queue can't be switched between self-hosted and hosted"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("queue_id",
+				mcp.Required(),
+				mcp.Description("The id of the queue"),
+			),
+			mcp.WithString("description",
+				mcp.Description("The new description"),
+			),
+			withQueueAgentParams(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Update Cluster Queue",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.UpdateClusterQueue")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			queueID, err := request.RequireString("queue_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			hostedAgents, err := hostedAgentsParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			update := ClusterQueueUpdate{
+				Description:        request.GetString("description", ""),
+				RetryAgentAffinity: request.GetString("retry_agent_affinity", ""),
+				HostedAgents:       hostedAgents,
+			}
+			if update.Description == "" && update.RetryAgentAffinity == "" && update.HostedAgents == nil {
+				return mcp.NewToolResultError("nothing to update, provide at least one field to change"), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.String("queue_id", queueID),
+				attribute.Bool("hosted", hostedAgents != nil),
+			)
+
+			queue, resp, err := client.Update(ctx, org, clusterID, queueID, update)
+			if err != nil {
+				return apiErrorResult("update cluster queue", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("update cluster queue", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&queue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster queue response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// withQueueAgentParams adds the parameters which configure the agents of a queue
+func withQueueAgentParams() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString("retry_agent_affinity",
+			mcp.Description("Whether retried jobs prefer the agent they last ran on (prefer-warmest) or a different agent (prefer-different)"),
+			mcp.Enum(RetryAgentAffinityPreferWarmest, RetryAgentAffinityPreferDifferent),
+		)(t)
+		mcp.WithString("hosted_agents_instance_shape",
+			mcp.Description("The instance shape of the hosted agents, e.g. LINUX_AMD64_2X4 or MACOS_M2_4X7"),
+		)(t)
+		mcp.WithString("hosted_agents_linux_agent_image_ref",
+			mcp.Description("The container image Linux hosted agents run jobs in"),
+		)(t)
+		mcp.WithString("hosted_agents_mac_xcode_version",
+			mcp.Description("The Xcode version macOS hosted agents run jobs with"),
+		)(t)
+	}
+}
+
+// hostedAgentsParams reads the hosted agent settings, checking any platform specific settings match
+// the instance shape. It returns nil when none are set.
+func hostedAgentsParams(request mcp.CallToolRequest) (*HostedAgents, error) {
+	shape := strings.ToUpper(request.GetString("hosted_agents_instance_shape", ""))
+	imageRef := request.GetString("hosted_agents_linux_agent_image_ref", "")
+	xcodeVersion := request.GetString("hosted_agents_mac_xcode_version", "")
+
+	if shape == "" && imageRef == "" && xcodeVersion == "" {
+		return nil, nil
+	}
+	if imageRef != "" && xcodeVersion != "" {
+		return nil, fmt.Errorf("hosted_agents_linux_agent_image_ref and hosted_agents_mac_xcode_version can't both be set")
+	}
+	if imageRef != "" && shape != "" && !strings.HasPrefix(shape, "LINUX_") {
+		return nil, fmt.Errorf("hosted_agents_linux_agent_image_ref needs a LINUX_ instance shape, not %s", shape)
+	}
+	if xcodeVersion != "" && shape != "" && !strings.HasPrefix(shape, "MACOS_") {
+		return nil, fmt.Errorf("hosted_agents_mac_xcode_version needs a MACOS_ instance shape, not %s", shape)
+	}
+
+	hostedAgents := &HostedAgents{InstanceShape: shape}
+	if imageRef != "" {
+		hostedAgents.Linux = &HostedAgentsLinux{AgentImageRef: imageRef}
+	}
+	if xcodeVersion != "" {
+		hostedAgents.Mac = &HostedAgentsMacOS{XcodeVersion: xcodeVersion}
+	}
+	return hostedAgents, nil
+}
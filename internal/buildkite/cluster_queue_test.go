@@ -2,6 +2,7 @@ package buildkite
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -10,8 +11,12 @@ import (
 )
 
 type mockClusterQueuesClient struct {
-	ListFunc func(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error)
-	GetFunc  func(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error)
+	ListFunc   func(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error)
+	GetFunc    func(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error)
+	PauseFunc  func(ctx context.Context, org, clusterID, queueID string, qp buildkite.ClusterQueuePause) (buildkite.ClusterQueue, *buildkite.Response, error)
+	ResumeFunc func(ctx context.Context, org, clusterID, queueID string) (*buildkite.Response, error)
+	CreateFunc func(ctx context.Context, org, clusterID string, qc ClusterQueueCreate) (HostedClusterQueue, *buildkite.Response, error)
+	UpdateFunc func(ctx context.Context, org, clusterID, queueID string, qu ClusterQueueUpdate) (HostedClusterQueue, *buildkite.Response, error)
 }
 
 func (m *mockClusterQueuesClient) List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error) {
@@ -27,6 +32,34 @@ func (m *mockClusterQueuesClient) Get(ctx context.Context, org, clusterID, queue
 	return buildkite.ClusterQueue{}, nil, nil
 }
 
+func (m *mockClusterQueuesClient) Pause(ctx context.Context, org, clusterID, queueID string, qp buildkite.ClusterQueuePause) (buildkite.ClusterQueue, *buildkite.Response, error) {
+	if m.PauseFunc != nil {
+		return m.PauseFunc(ctx, org, clusterID, queueID, qp)
+	}
+	return buildkite.ClusterQueue{}, nil, nil
+}
+
+func (m *mockClusterQueuesClient) Resume(ctx context.Context, org, clusterID, queueID string) (*buildkite.Response, error) {
+	if m.ResumeFunc != nil {
+		return m.ResumeFunc(ctx, org, clusterID, queueID)
+	}
+	return nil, nil
+}
+
+func (m *mockClusterQueuesClient) Create(ctx context.Context, org, clusterID string, qc ClusterQueueCreate) (HostedClusterQueue, *buildkite.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org, clusterID, qc)
+	}
+	return HostedClusterQueue{}, nil, nil
+}
+
+func (m *mockClusterQueuesClient) Update(ctx context.Context, org, clusterID, queueID string, qu ClusterQueueUpdate) (HostedClusterQueue, *buildkite.Response, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, org, clusterID, queueID, qu)
+	}
+	return HostedClusterQueue{}, nil, nil
+}
+
 var _ ClusterQueuesClient = (*mockClusterQueuesClient)(nil)
 
 func TestListClusterQueues(t *testing.T) {
@@ -93,3 +126,234 @@ func TestGetClusterQueue(t *testing.T) {
 	textContent := getTextResult(t, result)
 	assert.Equal("{\"id\":\"queue-id\",\"dispatch_paused\":false,\"created_by\":{}}", textContent.Text)
 }
+
+func TestPauseQueueDispatch(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var pausedWith []string
+	client := &mockClusterQueuesClient{
+		GetFunc: func(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error) {
+			return buildkite.ClusterQueue{
+					ID:             queueID,
+					Key:            "default",
+					DispatchPaused: queueID == "paused-queue",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		PauseFunc: func(ctx context.Context, org, clusterID, queueID string, qp buildkite.ClusterQueuePause) (buildkite.ClusterQueue, *buildkite.Response, error) {
+			pausedWith = append(pausedWith, qp.Note)
+			return buildkite.ClusterQueue{
+					ID:                 queueID,
+					Key:                "default",
+					DispatchPaused:     true,
+					DispatchPausedNote: qp.Note,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := PauseQueueDispatch(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.Equal("pause_queue_dispatch", tool.Name)
+	assert.Contains(tool.InputSchema.Required, "reason")
+
+	request := createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"queue_id":   "queue-id",
+		"reason":     "agents crash looping",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.Equal(`{"queue_id":"queue-id","key":"default","changed":true,"before":{"paused":false},"after":{"paused":true,"note":"agents crash looping"}}`, getTextResult(t, result).Text)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"queue_id":   "paused-queue",
+		"reason":     "agents crash looping",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.Contains(getTextResult(t, result).Text, `"changed":false`)
+	assert.Equal([]string{"agents crash looping"}, pausedWith)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"queue_id":   "queue-id",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+}
+
+func TestResumeQueueDispatch(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	paused := true
+	client := &mockClusterQueuesClient{
+		GetFunc: func(ctx context.Context, org, clusterID, queueID string) (buildkite.ClusterQueue, *buildkite.Response, error) {
+			queue := buildkite.ClusterQueue{ID: queueID, Key: "default", DispatchPaused: paused}
+			if paused {
+				queue.DispatchPausedNote = "agents crash looping"
+			}
+			return queue, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+		ResumeFunc: func(ctx context.Context, org, clusterID, queueID string) (*buildkite.Response, error) {
+			paused = false
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := ResumeQueueDispatch(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"queue_id":   "queue-id",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.Equal(`{"queue_id":"queue-id","key":"default","changed":true,"before":{"paused":true,"note":"agents crash looping"},"after":{"paused":false}}`, getTextResult(t, result).Text)
+
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.Contains(getTextResult(t, result).Text, `"changed":false`)
+}
+
+func TestCreateClusterQueue(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var created ClusterQueueCreate
+	client := &mockClusterQueuesClient{
+		CreateFunc: func(ctx context.Context, org, clusterID string, qc ClusterQueueCreate) (HostedClusterQueue, *buildkite.Response, error) {
+			created = qc
+			return HostedClusterQueue{
+					ClusterQueue: buildkite.ClusterQueue{ID: "queue-id", Key: qc.Key},
+					Hosted:       true,
+					HostedAgents: json.RawMessage(`{"instance_shape":{"name":"LINUX_AMD64_2X4"}}`),
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 201,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := CreateClusterQueue(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.False(*tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":                                 "org",
+		"cluster_id":                          "cluster-id",
+		"key":                                 "linux-small",
+		"retry_agent_affinity":                "prefer-different",
+		"hosted_agents_instance_shape":        "linux_amd64_2x4",
+		"hosted_agents_linux_agent_image_ref": "ubuntu:24.04",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.Equal(`{"id":"queue-id","key":"linux-small","dispatch_paused":false,"created_by":{},"hosted":true,"hosted_agents":{"instance_shape":{"name":"LINUX_AMD64_2X4"}}}`, getTextResult(t, result).Text)
+	assert.Equal(ClusterQueueCreate{
+		Key:                "linux-small",
+		RetryAgentAffinity: "prefer-different",
+		HostedAgents: &HostedAgents{
+			InstanceShape: "LINUX_AMD64_2X4",
+			Linux:         &HostedAgentsLinux{AgentImageRef: "ubuntu:24.04"},
+		},
+	}, created)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":                             "org",
+		"cluster_id":                      "cluster-id",
+		"key":                             "mac",
+		"hosted_agents_mac_xcode_version": "16.2",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "hosted_agents_instance_shape is required")
+
+	request = createMCPRequest(t, map[string]any{
+		"org":                             "org",
+		"cluster_id":                      "cluster-id",
+		"key":                             "mac",
+		"hosted_agents_instance_shape":    "LINUX_AMD64_2X4",
+		"hosted_agents_mac_xcode_version": "16.2",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "needs a MACOS_ instance shape")
+}
+
+func TestUpdateClusterQueue(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var updated ClusterQueueUpdate
+	client := &mockClusterQueuesClient{
+		UpdateFunc: func(ctx context.Context, org, clusterID, queueID string, qu ClusterQueueUpdate) (HostedClusterQueue, *buildkite.Response, error) {
+			updated = qu
+			return HostedClusterQueue{
+					ClusterQueue: buildkite.ClusterQueue{ID: queueID},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := UpdateClusterQueue(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":                             "org",
+		"cluster_id":                      "cluster-id",
+		"queue_id":                        "queue-id",
+		"hosted_agents_mac_xcode_version": "16.2",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.False(result.IsError)
+	assert.Equal(&HostedAgents{Mac: &HostedAgentsMacOS{XcodeVersion: "16.2"}}, updated.HostedAgents)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"queue_id":   "queue-id",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "nothing to update")
+}
@@ -0,0 +1,341 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ClusterTokensClient interface {
+	List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterTokensListOptions) ([]ClusterAgentToken, *buildkite.Response, error)
+	Create(ctx context.Context, org, clusterID string, ctc ClusterTokenCreate) (ClusterAgentToken, *buildkite.Response, error)
+	Delete(ctx context.Context, org, clusterID, tokenID string) (*buildkite.Response, error)
+}
+
+// ClusterTokenCreate creates an agent token for a cluster. AllowedIPAddresses is a space separated
+// list of CIDR ranges agents may connect from.
+type ClusterTokenCreate struct {
+	Description        string     `json:"description"`
+	AllowedIPAddresses string     `json:"allowed_ip_addresses,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
+
+// ClusterAgentToken is a cluster's agent token along with its expiry, which go-buildkite doesn't decode.
+// The secret Token is only returned when the token is created.
+type ClusterAgentToken struct {
+	buildkite.ClusterToken
+	ExpiresAt *buildkite.Timestamp `json:"expires_at,omitempty"`
+}
+
+// ClusterTokensClientAdapter adds token expiry, which go-buildkite doesn't support, to the cluster
+// tokens service
+type ClusterTokensClientAdapter struct {
+	*buildkite.Client
+}
+
+// List implements ClusterTokensClient
+func (a *ClusterTokensClientAdapter) List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterTokensListOptions) ([]ClusterAgentToken, *buildkite.Response, error) {
+	u := fmt.Sprintf("v2/organizations/%s/clusters/%s/tokens", org, clusterID)
+	if opts != nil {
+		query := url.Values{}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.PerPage > 0 {
+			query.Set("per_page", strconv.Itoa(opts.PerPage))
+		}
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+	}
+
+	req, err := a.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tokens []ClusterAgentToken
+	resp, err := a.Do(req, &tokens)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return tokens, resp, nil
+}
+
+// Create implements ClusterTokensClient
+func (a *ClusterTokensClientAdapter) Create(ctx context.Context, org, clusterID string, ctc ClusterTokenCreate) (ClusterAgentToken, *buildkite.Response, error) {
+	u := fmt.Sprintf("v2/organizations/%s/clusters/%s/tokens", org, clusterID)
+	req, err := a.NewRequest(ctx, http.MethodPost, u, ctc)
+	if err != nil {
+		return ClusterAgentToken{}, nil, err
+	}
+
+	var token ClusterAgentToken
+	resp, err := a.Do(req, &token)
+	if err != nil {
+		return ClusterAgentToken{}, resp, err
+	}
+
+	return token, resp, nil
+}
+
+// Delete implements ClusterTokensClient
+func (a *ClusterTokensClientAdapter) Delete(ctx context.Context, org, clusterID, tokenID string) (*buildkite.Response, error) {
+	return a.ClusterTokens.Delete(ctx, org, clusterID, tokenID)
+}
+
+func ListClusterTokens(ctx context.Context, client ClusterTokensClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_cluster_tokens",
+			mcp.WithDescription("List the agent tokens of a cluster with their descriptions, allowed IP ranges, expiry and creation details. The secret token values are never included"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			withPagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "List Cluster Tokens",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ListClusterTokens")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			paginationParams, err := optionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.Int("page", paginationParams.Page),
+				attribute.Int("per_page", paginationParams.PerPage),
+			)
+
+			tokens, resp, err := client.List(ctx, org, clusterID, &buildkite.ClusterTokensListOptions{
+				ListOptions: paginationParams,
+			})
+			if err != nil {
+				return apiErrorResult("list cluster tokens", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list cluster tokens", resp, nil), nil
+			}
+
+			// the API doesn't return secrets when listing, but make sure one can't slip through
+			for i := range tokens {
+				tokens[i].Token = ""
+			}
+
+			result := PaginatedResult[ClusterAgentToken]{
+				Items: tokens,
+				Headers: map[string]string{
+					"Link": resp.Header.Get("Link"),
+				},
+			}
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster tokens response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func CreateClusterToken(ctx context.Context, client ClusterTokensClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_cluster_token",
+			mcp.WithDescription("Create an agent token for a cluster, which agents use to register with the cluster's queues. The response contains the secret token, which is sensitive and only returned this once: pass it on to the user to store securely and don't repeat it elsewhere"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("description",
+				mcp.Required(),
+				mcp.Description("A description of what the token is for, e.g. the agent fleet that uses it"),
+			),
+			mcp.WithArray("allowed_ip_addresses",
+				mcp.Description("CIDR ranges agents using the token may connect from, e.g. 10.0.0.0/8 (default any)"),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+			mcp.WithString("expires_at",
+				mcp.Description("When the token expires, as an RFC3339 timestamp (default never)"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Create Cluster Token",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				IdempotentHint:  mcp.ToBoolPtr(false),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.CreateClusterToken")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			description, err := request.RequireString("description")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			create := ClusterTokenCreate{Description: description}
+
+			ranges := request.GetStringSlice("allowed_ip_addresses", nil)
+			for _, r := range ranges {
+				if _, err := netip.ParsePrefix(r); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid allowed_ip_addresses range %q, expected CIDR notation like 10.0.0.0/8", r)), nil
+				}
+			}
+			create.AllowedIPAddresses = strings.Join(ranges, " ")
+
+			if value := request.GetString("expires_at", ""); value != "" {
+				expiresAt, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid expires_at, expected an RFC3339 timestamp: %s", err)), nil
+				}
+				if !expiresAt.After(time.Now()) {
+					return mcp.NewToolResultError("expires_at must be in the future"), nil
+				}
+				create.ExpiresAt = &expiresAt
+			}
+
+			// the token is a secret, so only identifiers are recorded on the span
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.Bool("expires", create.ExpiresAt != nil),
+			)
+
+			token, resp, err := client.Create(ctx, org, clusterID, create)
+			if err != nil {
+				return apiErrorResult("create cluster token", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusCreated {
+				return apiErrorResult("create cluster token", resp, nil), nil
+			}
+
+			span.SetAttributes(attribute.String("token_id", token.ID))
+
+			r, err := json.Marshal(&token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster token: %w", err)
+			}
+
+			return sensitiveToolResult(string(r)), nil
+		}
+}
+
+func RevokeClusterToken(ctx context.Context, client ClusterTokensClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("revoke_cluster_token",
+			mcp.WithDescription("Revoke a cluster agent token. Agents can no longer register with it, and connected agents using it are disconnected"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("token_id",
+				mcp.Required(),
+				mcp.Description("The id of the token, as returned by list_cluster_tokens"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Revoke Cluster Token",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.RevokeClusterToken")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			tokenID, err := request.RequireString("token_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+				attribute.String("token_id", tokenID),
+			)
+
+			resp, err := client.Delete(ctx, org, clusterID, tokenID)
+			if err != nil {
+				return apiErrorResult("revoke cluster token", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+				return apiErrorResult("revoke cluster token", resp, nil), nil
+			}
+
+			r, err := json.Marshal(map[string]any{
+				"token_id": tokenID,
+				"revoked":  true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal revoke cluster token response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// sensitiveToolResult flags a result containing a secret in its metadata, so clients can keep it out
+// of logs and transcripts
+func sensitiveToolResult(text string) *mcp.CallToolResult {
+	result := mcp.NewToolResultText(text)
+	result.Meta = map[string]any{"sensitive": true}
+	return result
+}
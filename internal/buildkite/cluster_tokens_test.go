@@ -0,0 +1,175 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type mockClusterTokensClient struct {
+	ListFunc   func(ctx context.Context, org, clusterID string, opts *buildkite.ClusterTokensListOptions) ([]ClusterAgentToken, *buildkite.Response, error)
+	CreateFunc func(ctx context.Context, org, clusterID string, ctc ClusterTokenCreate) (ClusterAgentToken, *buildkite.Response, error)
+	DeleteFunc func(ctx context.Context, org, clusterID, tokenID string) (*buildkite.Response, error)
+}
+
+func (m *mockClusterTokensClient) List(ctx context.Context, org, clusterID string, opts *buildkite.ClusterTokensListOptions) ([]ClusterAgentToken, *buildkite.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, org, clusterID, opts)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockClusterTokensClient) Create(ctx context.Context, org, clusterID string, ctc ClusterTokenCreate) (ClusterAgentToken, *buildkite.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org, clusterID, ctc)
+	}
+	return ClusterAgentToken{}, nil, nil
+}
+
+func (m *mockClusterTokensClient) Delete(ctx context.Context, org, clusterID, tokenID string) (*buildkite.Response, error) {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, org, clusterID, tokenID)
+	}
+	return nil, nil
+}
+
+var _ ClusterTokensClient = (*mockClusterTokensClient)(nil)
+
+func TestListClusterTokens(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &mockClusterTokensClient{
+		ListFunc: func(ctx context.Context, org, clusterID string, opts *buildkite.ClusterTokensListOptions) ([]ClusterAgentToken, *buildkite.Response, error) {
+			return []ClusterAgentToken{
+					{ClusterToken: buildkite.ClusterToken{ID: "token-id", Description: "fleet", Token: "secret"}},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := ListClusterTokens(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"headers":{"Link":""},"items":[{"id":"token-id","description":"fleet","created_by":{}}]}`, textContent.Text)
+}
+
+func TestCreateClusterToken(t *testing.T) {
+	assert := require.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	ctx := context.Background()
+
+	var created ClusterTokenCreate
+	client := &mockClusterTokensClient{
+		CreateFunc: func(ctx context.Context, org, clusterID string, ctc ClusterTokenCreate) (ClusterAgentToken, *buildkite.Response, error) {
+			created = ctc
+			return ClusterAgentToken{
+					ClusterToken: buildkite.ClusterToken{ID: "token-id", Description: ctc.Description, Token: "super-secret-token"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 201,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := CreateClusterToken(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.False(*tool.Annotations.ReadOnlyHint)
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":                  "org",
+		"cluster_id":           "cluster-id",
+		"description":          "fleet",
+		"allowed_ip_addresses": []any{"10.0.0.0/8", "192.168.1.0/24"},
+		"expires_at":           expiresAt.Format(time.RFC3339),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.False(result.IsError)
+	assert.Equal(true, result.Meta["sensitive"])
+
+	var token ClusterAgentToken
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &token))
+	assert.Equal("super-secret-token", token.Token)
+
+	assert.Equal("10.0.0.0/8 192.168.1.0/24", created.AllowedIPAddresses)
+	assert.True(expiresAt.Equal(*created.ExpiresAt))
+
+	spans := recorder.Ended()
+	assert.Len(spans, 1)
+	for _, attr := range spans[0].Attributes() {
+		assert.False(strings.Contains(attr.Value.Emit(), "super-secret-token"), "span attribute %s contains the token", attr.Key)
+	}
+
+	request = createMCPRequest(t, map[string]any{
+		"org":                  "org",
+		"cluster_id":           "cluster-id",
+		"description":          "fleet",
+		"allowed_ip_addresses": []any{"10.0.0.1"},
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "CIDR notation")
+}
+
+func TestRevokeClusterToken(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &mockClusterTokensClient{
+		DeleteFunc: func(ctx context.Context, org, clusterID, tokenID string) (*buildkite.Response, error) {
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := RevokeClusterToken(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.True(*tool.Annotations.DestructiveHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+		"token_id":   "token-id",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"revoked":true,"token_id":"token-id"}`, textContent.Text)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -15,6 +16,8 @@ import (
 type ClustersClient interface {
 	List(ctx context.Context, org string, opts *buildkite.ClustersListOptions) ([]buildkite.Cluster, *buildkite.Response, error)
 	Get(ctx context.Context, org, id string) (buildkite.Cluster, *buildkite.Response, error)
+	Create(ctx context.Context, org string, cc buildkite.ClusterCreate) (buildkite.Cluster, *buildkite.Response, error)
+	Update(ctx context.Context, org, id string, cu buildkite.ClusterUpdate) (buildkite.Cluster, *buildkite.Response, error)
 }
 
 func ListClusters(ctx context.Context, client ClustersClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
@@ -52,11 +55,11 @@ func ListClusters(ctx context.Context, client ClustersClient) (tool mcp.Tool, ha
 				ListOptions: paginationParams,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list clusters", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("Failed to list clusters"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list clusters", resp, nil), nil
 			}
 			if len(clusters) == 0 {
 				return mcp.NewToolResultText("No clusters found"), nil
@@ -113,11 +116,11 @@ func GetCluster(ctx context.Context, client ClustersClient) (tool mcp.Tool, hand
 
 			cluster, resp, err := client.Get(ctx, org, clusterID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get cluster", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("Failed to get cluster"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get cluster", resp, nil), nil
 			}
 
 			r, err := json.Marshal(cluster)
@@ -127,3 +130,153 @@ func GetCluster(ctx context.Context, client ClustersClient) (tool mcp.Tool, hand
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+func CreateCluster(ctx context.Context, client ClustersClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_cluster",
+			mcp.WithDescription("Create a new cluster in an organization. A cluster groups queues and the agents which run their jobs; create a queue in it with create_cluster_queue"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the cluster"),
+			),
+			mcp.WithString("description",
+				mcp.Description("A description of the cluster"),
+			),
+			mcp.WithString("emoji",
+				mcp.Description("An emoji to show with the cluster, e.g. :rocket:"),
+			),
+			mcp.WithString("color",
+				mcp.Description("A hex color to show with the cluster, e.g. #A9CCE3"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Create Cluster",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				IdempotentHint:  mcp.ToBoolPtr(false),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.CreateCluster")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			name, err := request.RequireString("name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("name", name),
+			)
+
+			cluster, resp, err := client.Create(ctx, org, buildkite.ClusterCreate{
+				Name:        name,
+				Description: request.GetString("description", ""),
+				Emoji:       request.GetString("emoji", ""),
+				Color:       request.GetString("color", ""),
+			})
+			if err != nil {
+				return apiErrorResult("create cluster", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusCreated {
+				return apiErrorResult("create cluster", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&cluster)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func UpdateCluster(ctx context.Context, client ClustersClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_cluster",
+			mcp.WithDescription("Update the settings of an existing cluster, including which of its queues is the default. Only the fields provided are changed"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Required(),
+				mcp.Description("The id of the cluster"),
+			),
+			mcp.WithString("name",
+				mcp.Description("The new name of the cluster"),
+			),
+			mcp.WithString("description",
+				mcp.Description("The new description"),
+			),
+			mcp.WithString("emoji",
+				mcp.Description("The new emoji, e.g. :rocket:"),
+			),
+			mcp.WithString("color",
+				mcp.Description("The new hex color, e.g. #A9CCE3"),
+			),
+			mcp.WithString("default_queue_id",
+				mcp.Description("The id of the queue in this cluster which jobs without a queue tag run on"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Update Cluster",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.UpdateCluster")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			clusterID, err := request.RequireString("cluster_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			update := buildkite.ClusterUpdate{
+				Name:           request.GetString("name", ""),
+				Description:    request.GetString("description", ""),
+				Emoji:          request.GetString("emoji", ""),
+				Color:          request.GetString("color", ""),
+				DefaultQueueID: request.GetString("default_queue_id", ""),
+			}
+			if update == (buildkite.ClusterUpdate{}) {
+				return mcp.NewToolResultError("nothing to update, provide at least one field to change"), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+			)
+
+			cluster, resp, err := client.Update(ctx, org, clusterID, update)
+			if err != nil {
+				return apiErrorResult("update cluster", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("update cluster", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&cluster)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
@@ -12,8 +12,10 @@ import (
 var _ ClustersClient = (*mockClustersClient)(nil)
 
 type mockClustersClient struct {
-	ListFunc func(ctx context.Context, org string, opts *buildkite.ClustersListOptions) ([]buildkite.Cluster, *buildkite.Response, error)
-	GetFunc  func(ctx context.Context, org, id string) (buildkite.Cluster, *buildkite.Response, error)
+	ListFunc   func(ctx context.Context, org string, opts *buildkite.ClustersListOptions) ([]buildkite.Cluster, *buildkite.Response, error)
+	GetFunc    func(ctx context.Context, org, id string) (buildkite.Cluster, *buildkite.Response, error)
+	CreateFunc func(ctx context.Context, org string, cc buildkite.ClusterCreate) (buildkite.Cluster, *buildkite.Response, error)
+	UpdateFunc func(ctx context.Context, org, id string, cu buildkite.ClusterUpdate) (buildkite.Cluster, *buildkite.Response, error)
 }
 
 func (m *mockClustersClient) List(ctx context.Context, org string, opts *buildkite.ClustersListOptions) ([]buildkite.Cluster, *buildkite.Response, error) {
@@ -29,6 +31,20 @@ func (m *mockClustersClient) Get(ctx context.Context, org, id string) (buildkite
 	return buildkite.Cluster{}, nil, nil
 }
 
+func (m *mockClustersClient) Create(ctx context.Context, org string, cc buildkite.ClusterCreate) (buildkite.Cluster, *buildkite.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org, cc)
+	}
+	return buildkite.Cluster{}, nil, nil
+}
+
+func (m *mockClustersClient) Update(ctx context.Context, org, id string, cu buildkite.ClusterUpdate) (buildkite.Cluster, *buildkite.Response, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, org, id, cu)
+	}
+	return buildkite.Cluster{}, nil, nil
+}
+
 func TestListClusters(t *testing.T) {
 	assert := require.New(t)
 
@@ -93,3 +109,80 @@ func TestGetCluster(t *testing.T) {
 	textContent := getTextResult(t, result)
 	assert.Equal("{\"id\":\"cluster-id\",\"name\":\"cluster-name\",\"created_by\":{}}", textContent.Text)
 }
+
+func TestCreateCluster(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &mockClustersClient{
+		CreateFunc: func(ctx context.Context, org string, cc buildkite.ClusterCreate) (buildkite.Cluster, *buildkite.Response, error) {
+			assert.Equal(buildkite.ClusterCreate{Name: "Platform", Emoji: ":rocket:"}, cc)
+			return buildkite.Cluster{
+					ID:    "cluster-id",
+					Name:  cc.Name,
+					Emoji: cc.Emoji,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 201,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := CreateCluster(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.False(*tool.Annotations.ReadOnlyHint)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":   "org",
+		"name":  "Platform",
+		"emoji": ":rocket:",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"id":"cluster-id","name":"Platform","emoji":":rocket:","created_by":{}}`, textContent.Text)
+}
+
+func TestUpdateCluster(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &mockClustersClient{
+		UpdateFunc: func(ctx context.Context, org, id string, cu buildkite.ClusterUpdate) (buildkite.Cluster, *buildkite.Response, error) {
+			return buildkite.Cluster{
+					ID:             id,
+					DefaultQueueID: cu.DefaultQueueID,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := UpdateCluster(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":              "org",
+		"cluster_id":       "cluster-id",
+		"default_queue_id": "queue-id",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.Equal(`{"id":"cluster-id","default_queue_id":"queue-id","created_by":{}}`, textContent.Text)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+}
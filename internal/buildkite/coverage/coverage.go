@@ -0,0 +1,315 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownFormat is returned when data is not a recognised coverage report
+var ErrUnknownFormat = errors.New("unrecognised coverage format")
+
+// Format identifies the tool which produced a coverage report
+type Format string
+
+const (
+	FormatGo        Format = "go"
+	FormatLcov      Format = "lcov"
+	FormatCobertura Format = "cobertura"
+)
+
+// File is the coverage of a single source file. Go profiles count statements, lcov and Cobertura count lines.
+type File struct {
+	Path    string
+	Package string
+	Covered int
+	Total   int
+}
+
+// FileCoverage is the coverage of a single source file
+type FileCoverage struct {
+	Path    string  `json:"path"`
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// PackageCoverage is the coverage of all the files in a package or directory
+type PackageCoverage struct {
+	Package string  `json:"package"`
+	Files   int     `json:"files"`
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// Summary is the overall, per package and lowest file coverage of a report
+type Summary struct {
+	Format      Format            `json:"format"`
+	Covered     int               `json:"covered"`
+	Total       int               `json:"total"`
+	Percent     float64           `json:"percent"`
+	Packages    []PackageCoverage `json:"packages"`
+	LowestFiles []FileCoverage    `json:"lowest_files"`
+}
+
+// Parse detects the format of a coverage report from its content and returns the coverage of each file
+func Parse(data []byte) (Format, []File, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		files, err := parseGo(trimmed)
+		return FormatGo, files, err
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		files, err := parseCobertura(trimmed)
+		return FormatCobertura, files, err
+	case bytes.Contains(trimmed, []byte("SF:")) && bytes.Contains(trimmed, []byte("end_of_record")):
+		files, err := parseLcov(trimmed)
+		return FormatLcov, files, err
+	default:
+		return "", nil, ErrUnknownFormat
+	}
+}
+
+// parseGo reads a Go cover profile, where each line is "file:start.col,end.col statements count".
+// Profiles merged from several runs repeat blocks, so a block counts as covered if any run hit it.
+func parseGo(data []byte) ([]File, error) {
+	type block struct {
+		statements int
+		covered    bool
+	}
+
+	blocks := make(map[string]map[string]*block)
+	var order []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		colon := strings.LastIndex(line, ":")
+		if colon == -1 {
+			return nil, fmt.Errorf("invalid go coverage line %d: %q", lineNumber, line)
+		}
+
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid go coverage line %d: %q", lineNumber, line)
+		}
+
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid go coverage line %d: %w", lineNumber, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid go coverage line %d: %w", lineNumber, err)
+		}
+
+		file := line[:colon]
+		if blocks[file] == nil {
+			blocks[file] = make(map[string]*block)
+			order = append(order, file)
+		}
+
+		b := blocks[file][fields[0]]
+		if b == nil {
+			b = &block{statements: statements}
+			blocks[file][fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go coverage: %w", err)
+	}
+
+	files := make([]File, 0, len(order))
+	for _, name := range order {
+		f := File{Path: name, Package: path.Dir(name)}
+		for _, b := range blocks[name] {
+			f.Total += b.statements
+			if b.covered {
+				f.Covered += b.statements
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// parseLcov reads an lcov tracefile, preferring the LF/LH summary lines and falling back to counting DA lines
+func parseLcov(data []byte) ([]File, error) {
+	var (
+		files   []File
+		current *File
+		found   = -1
+		hit     = -1
+		daTotal int
+		daHit   int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, _ := strings.Cut(line, ":")
+
+		switch key {
+		case "SF":
+			current = &File{Path: value, Package: path.Dir(value)}
+			found, hit, daTotal, daHit = -1, -1, 0, 0
+		case "DA":
+			// DA:<line>,<hits>[,<checksum>]
+			parts := strings.Split(value, ",")
+			if len(parts) < 2 {
+				continue
+			}
+			daTotal++
+			if hits, err := strconv.Atoi(parts[1]); err == nil && hits > 0 {
+				daHit++
+			}
+		case "LF":
+			found, _ = strconv.Atoi(value)
+		case "LH":
+			hit, _ = strconv.Atoi(value)
+		case "end_of_record":
+			if current == nil {
+				continue
+			}
+			current.Total, current.Covered = daTotal, daHit
+			if found >= 0 && hit >= 0 {
+				current.Total, current.Covered = found, hit
+			}
+			files = append(files, *current)
+			current = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lcov coverage: %w", err)
+	}
+
+	return files, nil
+}
+
+type coberturaReport struct {
+	XMLName  xml.Name `xml:"coverage"`
+	Packages []struct {
+		Name    string `xml:"name,attr"`
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Hits string `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura reads a Cobertura XML report, counting lines per file as a file can hold several classes
+func parseCobertura(data []byte) ([]File, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		var unexpected xml.UnmarshalError
+		if errors.As(err, &unexpected) {
+			return nil, ErrUnknownFormat
+		}
+		return nil, fmt.Errorf("failed to parse cobertura xml: %w", err)
+	}
+
+	var files []File
+	index := make(map[string]int)
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			i, ok := index[class.Filename]
+			if !ok {
+				i = len(files)
+				index[class.Filename] = i
+				files = append(files, File{Path: class.Filename, Package: pkg.Name})
+			}
+
+			for _, line := range class.Lines {
+				files[i].Total++
+				if hits, err := strconv.ParseFloat(line.Hits, 64); err == nil && hits > 0 {
+					files[i].Covered++
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// Summarize totals the coverage of files by package and picks out the lowest covered files.
+// Files with nothing to cover are left out of the lowest files.
+func Summarize(format Format, files []File, lowest int) Summary {
+	summary := Summary{
+		Format:      format,
+		Packages:    make([]PackageCoverage, 0),
+		LowestFiles: make([]FileCoverage, 0),
+	}
+
+	packages := make(map[string]*PackageCoverage)
+	for _, f := range files {
+		summary.Covered += f.Covered
+		summary.Total += f.Total
+
+		pkg := packages[f.Package]
+		if pkg == nil {
+			pkg = &PackageCoverage{Package: f.Package}
+			packages[f.Package] = pkg
+		}
+		pkg.Files++
+		pkg.Covered += f.Covered
+		pkg.Total += f.Total
+
+		if f.Total > 0 {
+			summary.LowestFiles = append(summary.LowestFiles, FileCoverage{
+				Path:    f.Path,
+				Covered: f.Covered,
+				Total:   f.Total,
+				Percent: percent(f.Covered, f.Total),
+			})
+		}
+	}
+	summary.Percent = percent(summary.Covered, summary.Total)
+
+	for _, pkg := range packages {
+		pkg.Percent = percent(pkg.Covered, pkg.Total)
+		summary.Packages = append(summary.Packages, *pkg)
+	}
+	sort.Slice(summary.Packages, func(i, j int) bool {
+		return summary.Packages[i].Package < summary.Packages[j].Package
+	})
+
+	// lowest percentage first, then the most uncovered, so large untested files rank above small ones
+	sort.SliceStable(summary.LowestFiles, func(i, j int) bool {
+		a, b := summary.LowestFiles[i], summary.LowestFiles[j]
+		if a.Percent != b.Percent {
+			return a.Percent < b.Percent
+		}
+		return a.Total-a.Covered > b.Total-b.Covered
+	})
+	lowest = max(lowest, 0)
+	if len(summary.LowestFiles) > lowest {
+		summary.LowestFiles = summary.LowestFiles[:lowest]
+	}
+
+	return summary
+}
+
+// percent returns covered as a percentage of total, rounded to one decimal place
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(covered)/float64(total)*1000) / 10
+}
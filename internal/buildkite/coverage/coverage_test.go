@@ -0,0 +1,108 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGo(t *testing.T) {
+	assert := require.New(t)
+
+	profile := `mode: count
+github.com/org/repo/pkg/a.go:10.2,12.3 2 1
+github.com/org/repo/pkg/a.go:14.2,15.3 3 0
+github.com/org/repo/pkg/b.go:5.2,6.3 4 0
+github.com/org/repo/cmd/main.go:3.2,4.3 1 0
+github.com/org/repo/pkg/a.go:14.2,15.3 3 2
+`
+
+	format, files, err := Parse([]byte(profile))
+	assert.NoError(err)
+	assert.Equal(FormatGo, format)
+	assert.Equal([]File{
+		{Path: "github.com/org/repo/pkg/a.go", Package: "github.com/org/repo/pkg", Covered: 5, Total: 5},
+		{Path: "github.com/org/repo/pkg/b.go", Package: "github.com/org/repo/pkg", Covered: 0, Total: 4},
+		{Path: "github.com/org/repo/cmd/main.go", Package: "github.com/org/repo/cmd", Covered: 0, Total: 1},
+	}, files)
+
+	summary := Summarize(format, files, 1)
+	assert.Equal(50.0, summary.Percent)
+	assert.Equal([]PackageCoverage{
+		{Package: "github.com/org/repo/cmd", Files: 1, Covered: 0, Total: 1, Percent: 0},
+		{Package: "github.com/org/repo/pkg", Files: 2, Covered: 5, Total: 9, Percent: 55.6},
+	}, summary.Packages)
+	assert.Equal([]FileCoverage{{Path: "github.com/org/repo/pkg/b.go", Covered: 0, Total: 4, Percent: 0}}, summary.LowestFiles)
+}
+
+func TestSummarizeNegativeLowest(t *testing.T) {
+	assert := require.New(t)
+
+	files := []File{{Path: "a.go", Package: "pkg", Covered: 1, Total: 2}}
+
+	summary := Summarize(FormatGo, files, -1)
+	assert.Empty(summary.LowestFiles)
+	assert.Equal(50.0, summary.Percent)
+}
+
+func TestParseLcov(t *testing.T) {
+	assert := require.New(t)
+
+	tracefile := `TN:
+SF:src/app.js
+DA:1,1
+DA:2,0
+LF:2
+LH:1
+end_of_record
+SF:src/util/math.js
+DA:1,3
+DA:2,1
+DA:3,0
+DA:4,0
+end_of_record
+`
+
+	format, files, err := Parse([]byte(tracefile))
+	assert.NoError(err)
+	assert.Equal(FormatLcov, format)
+	assert.Equal([]File{
+		{Path: "src/app.js", Package: "src", Covered: 1, Total: 2},
+		{Path: "src/util/math.js", Package: "src/util", Covered: 2, Total: 4},
+	}, files)
+}
+
+func TestParseCobertura(t *testing.T) {
+	assert := require.New(t)
+
+	report := `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <packages>
+    <package name="app.models">
+      <classes>
+        <class name="User" filename="app/models/user.py">
+          <lines><line number="1" hits="1"/><line number="2" hits="0"/></lines>
+        </class>
+        <class name="Admin" filename="app/models/user.py">
+          <lines><line number="10" hits="4"/></lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+
+	format, files, err := Parse([]byte(report))
+	assert.NoError(err)
+	assert.Equal(FormatCobertura, format)
+	assert.Equal([]File{{Path: "app/models/user.py", Package: "app.models", Covered: 2, Total: 3}}, files)
+}
+
+func TestParseUnknown(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, err := Parse([]byte(`<testsuite name="junit"/>`))
+	assert.ErrorIs(err, ErrUnknownFormat)
+
+	_, _, err = Parse([]byte(`{"total": 1}`))
+	assert.ErrorIs(err, ErrUnknownFormat)
+}
@@ -0,0 +1,186 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/coverage"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultLowestFiles is how many of the least covered files are returned per report by default
+	defaultLowestFiles = 10
+	// maxLowestFiles caps how many of the least covered files can be returned per report
+	maxLowestFiles = 100
+	// maxCoverageArtifacts caps how many coverage artifacts are downloaded for a single build
+	maxCoverageArtifacts = 20
+)
+
+// CoverageReport summarizes a single coverage artifact
+type CoverageReport struct {
+	Path  string `json:"path"`
+	JobID string `json:"job_id,omitempty"`
+	coverage.Summary
+}
+
+// CoverageResults are the coverage reports found among a build's artifacts
+type CoverageResults struct {
+	Reports   []CoverageReport `json:"reports"`
+	Omitted   int              `json:"omitted_files,omitempty"`
+	Truncated bool             `json:"truncated,omitempty"`
+	Errors    []string         `json:"errors,omitempty"`
+}
+
+func GetCoverageSummary(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_coverage_summary",
+			mcp.WithDescription("Find the coverage reports uploaded as artifacts by a build (Go coverage profiles, lcov tracefiles and Cobertura XML) and summarise them, returning the overall and per-package coverage percentages and the lowest covered files of each report"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The build number"),
+			),
+			mcp.WithString("job_uuid",
+				mcp.Description("Only look at artifacts uploaded by this job"),
+			),
+			mcp.WithString("path_glob",
+				mcp.Description("Only summarise artifacts whose path matches this glob, e.g. 'coverage/*.out' (default common coverage file names)"),
+			),
+			mcp.WithNumber("lowest_files",
+				mcp.Description("Number of the least covered files to return per report (default 10)"),
+				mcp.Min(0),
+				mcp.Max(maxLowestFiles),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get Coverage Summary",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetCoverageSummary")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			jobUUID := request.GetString("job_uuid", "")
+			pathGlob := request.GetString("path_glob", "")
+			if _, err := path.Match(pathGlob, ""); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid path_glob: %s", err)), nil
+			}
+
+			lowestFiles := request.GetInt("lowest_files", defaultLowestFiles)
+			if lowestFiles < 0 || lowestFiles > maxLowestFiles {
+				return mcp.NewToolResultError(fmt.Sprintf("lowest_files must be between 0 and %d", maxLowestFiles)), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.String("job_uuid", jobUUID),
+				attribute.String("path_glob", pathGlob),
+				attribute.Int("lowest_files", lowestFiles),
+			)
+
+			artifacts, truncated, err := listAllArtifacts(ctx, client, org, pipelineSlug, buildNumber, jobUUID)
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			var candidates []buildkite.Artifact
+			for _, artifact := range artifacts {
+				if isCoverageCandidate(artifact, pathGlob) {
+					candidates = append(candidates, artifact)
+				}
+			}
+
+			result := CoverageResults{
+				Reports:   make([]CoverageReport, 0),
+				Truncated: truncated,
+			}
+			if len(candidates) > maxCoverageArtifacts {
+				result.Omitted = len(candidates) - maxCoverageArtifacts
+				candidates = candidates[:maxCoverageArtifacts]
+			}
+
+			contents, errs := downloadArtifacts(ctx, client, candidates)
+			result.Errors = errs
+
+			for i, content := range contents {
+				if content == nil {
+					continue
+				}
+
+				format, files, err := coverage.Parse(content)
+				if errors.Is(err, coverage.ErrUnknownFormat) {
+					continue
+				}
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", candidates[i].Path, err))
+					continue
+				}
+
+				result.Reports = append(result.Reports, CoverageReport{
+					Path:    candidates[i].Path,
+					JobID:   candidates[i].JobID,
+					Summary: coverage.Summarize(format, files, lowestFiles),
+				})
+			}
+
+			span.SetAttributes(attribute.Int("reports", len(result.Reports)))
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal coverage summary: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// isCoverageCandidate matches the file names coverage tools write by default, such as coverage.out,
+// lcov.info and cobertura.xml, unless a glob is given
+func isCoverageCandidate(artifact buildkite.Artifact, pathGlob string) bool {
+	if pathGlob != "" {
+		matched, _ := path.Match(pathGlob, artifact.Path)
+		return matched
+	}
+
+	name := strings.ToLower(path.Base(artifact.Path))
+	switch path.Ext(name) {
+	case ".out", ".cov", ".coverprofile":
+		return strings.Contains(name, "cover")
+	case ".info", ".lcov":
+		return strings.Contains(name, "lcov") || strings.Contains(name, "cover")
+	case ".xml":
+		return strings.Contains(name, "cobertura") || strings.Contains(name, "coverage")
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCoverageSummary(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	contents := map[string]string{
+		"https://example.com/coverage.out": "mode: set\nexample.com/pkg/a.go:1.1,2.1 3 1\nexample.com/pkg/b.go:1.1,2.1 1 0\n",
+		"https://example.com/lcov.info":    "SF:src/app.js\nLF:4\nLH:1\nend_of_record\n",
+		"https://example.com/coverage.xml": `<testsuite name="not coverage"/>`,
+	}
+
+	client := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			return []buildkite.Artifact{
+					{JobID: "job1", Path: "coverage.out", DownloadURL: "https://example.com/coverage.out"},
+					{JobID: "job2", Path: "web/coverage/lcov.info", DownloadURL: "https://example.com/lcov.info"},
+					{JobID: "job2", Path: "reports/coverage.xml", DownloadURL: "https://example.com/coverage.xml"},
+					{JobID: "job2", Path: "reports/junit.xml", DownloadURL: "https://example.com/junit.xml"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			content, ok := contents[url]
+			if !ok {
+				return nil, fmt.Errorf("unexpected download of %s", url)
+			}
+			_, err := writer.Write([]byte(content))
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, err
+		},
+	}
+
+	tool, handler := GetCoverageSummary(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "1",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var results CoverageResults
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &results))
+	assert.Empty(results.Errors)
+	assert.Len(results.Reports, 2)
+
+	goReport := results.Reports[0]
+	assert.Equal("coverage.out", goReport.Path)
+	assert.Equal("job1", goReport.JobID)
+	assert.Equal("go", string(goReport.Format))
+	assert.Equal(75.0, goReport.Percent)
+	assert.Len(goReport.Packages, 1)
+	assert.Equal("example.com/pkg/b.go", goReport.LowestFiles[0].Path)
+
+	lcovReport := results.Reports[1]
+	assert.Equal("web/coverage/lcov.info", lcovReport.Path)
+	assert.Equal(25.0, lcovReport.Percent)
+}
+
+func TestGetCoverageSummary_InvalidLowestFiles(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			return nil, nil, fmt.Errorf("unexpected request")
+		},
+	}
+
+	_, handler := GetCoverageSummary(ctx, client)
+
+	for _, lowest := range []float64{-1, 101} {
+		result, err := handler(ctx, createMCPRequest(t, map[string]any{
+			"org":           "org",
+			"pipeline_slug": "pipeline",
+			"build_number":  "1",
+			"lowest_files":  lowest,
+		}))
+		assert.NoError(err)
+		assert.True(result.IsError)
+		assert.Equal("lowest_files must be between 0 and 100", getTextResult(t, result).Text)
+	}
+}
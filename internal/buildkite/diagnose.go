@@ -0,0 +1,302 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/joblogs"
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/markdown"
+	"github.com/buildkite/buildkite-mcp-server/internal/tokens"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultDiagnoseTokenBudget is the default size of a diagnose_build report
+	defaultDiagnoseTokenBudget = 4000
+	// maxDiagnosedJobs caps how many failed job logs are fetched for a single report
+	maxDiagnosedJobs = 10
+)
+
+// JobLogsClient describes the subset of the Buildkite client we need for job logs.
+type JobLogsClient interface {
+	GetJobLog(ctx context.Context, org, pipelineSlug, buildNumber, jobID string) (buildkite.JobLog, *buildkite.Response, error)
+}
+
+// DiagnosedJob is a failed job along with the tail of its log
+type DiagnosedJob struct {
+	FailedJobSummary
+	LogExcerpt   string `json:"log_excerpt,omitempty"`
+	LogTruncated bool   `json:"log_truncated,omitempty"`
+}
+
+// DiagnosedAnnotation is an error or warning annotation converted to Markdown
+type DiagnosedAnnotation struct {
+	Context string `json:"context,omitempty"`
+	Style   string `json:"style"`
+	Body    string `json:"body"`
+}
+
+// DiagnosedTest is a failing test reported to Test Engine
+type DiagnosedTest struct {
+	TestName      string `json:"test_name"`
+	Location      string `json:"location,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	TestURL       string `json:"test_url,omitempty"`
+}
+
+// BuildDiagnosis is a compact report explaining why a build failed
+type BuildDiagnosis struct {
+	Build        map[string]any        `json:"build"`
+	FailedJobs   []DiagnosedJob        `json:"failed_jobs"`
+	Annotations  []DiagnosedAnnotation `json:"annotations"`
+	FailingTests []DiagnosedTest       `json:"failing_tests"`
+	Omitted      map[string]int        `json:"omitted,omitempty"`
+	Errors       []string              `json:"errors,omitempty"`
+}
+
+func DiagnoseBuild(ctx context.Context, builds BuildsClient, jobLogs JobLogsClient, annotations AnnotationsClient, testExecutions TestExecutionsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("diagnose_build",
+			mcp.WithDescription("Diagnose why a build failed in a single call. Returns the failed jobs with the tail of their logs, error and warning annotations, and failing Test Engine tests, sized to fit a token budget"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The number of the build"),
+			),
+			mcp.WithNumber("max_tokens",
+				mcp.Description("Approximate token budget for the report (default 4000)"),
+				mcp.Min(500),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Diagnose Build",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.DiagnoseBuild")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			maxTokens := request.GetInt("max_tokens", defaultDiagnoseTokenBudget)
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.Int("max_tokens", maxTokens),
+			)
+
+			build, resp, err := builds.Get(ctx, org, pipelineSlug, buildNumber, &buildkite.BuildGetOptions{
+				IncludeTestEngine: true,
+			})
+			if err != nil {
+				return apiErrorResult("get build", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get build", resp, nil), nil
+			}
+
+			diagnosis := BuildDiagnosis{
+				Build: map[string]any{
+					"number":  build.Number,
+					"state":   build.State,
+					"branch":  build.Branch,
+					"commit":  build.Commit,
+					"message": build.Message,
+					"web_url": build.WebURL,
+				},
+				FailedJobs:   make([]DiagnosedJob, 0),
+				Annotations:  make([]DiagnosedAnnotation, 0),
+				FailingTests: make([]DiagnosedTest, 0),
+				Omitted:      make(map[string]int),
+			}
+
+			for _, job := range build.Jobs {
+				if !isFailedJob(job) {
+					continue
+				}
+				if len(diagnosis.FailedJobs) == maxDiagnosedJobs {
+					diagnosis.Omitted["failed_jobs"]++
+					continue
+				}
+				diagnosis.FailedJobs = append(diagnosis.FailedJobs, DiagnosedJob{FailedJobSummary: summarizeFailedJob(job)})
+			}
+
+			var testEngineRuns []buildkite.TestEngineRun
+			if build.TestEngine != nil {
+				testEngineRuns = build.TestEngine.Runs
+			}
+
+			// Split the budget between the three sections, the logs usually carry the most signal
+			logBudget := maxTokens / 2
+			annotationBudget := maxTokens / 5
+			testBudget := maxTokens - logBudget - annotationBudget
+
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				rawLogs  = make([]string, len(diagnosis.FailedJobs))
+				rawTests = make([][]buildkite.FailedExecution, len(testEngineRuns))
+				rawNotes []buildkite.Annotation
+			)
+
+			addError := func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				diagnosis.Errors = append(diagnosis.Errors, err.Error())
+			}
+
+			for i, job := range diagnosis.FailedJobs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					log, err := fetchProcessedJobLog(ctx, jobLogs, org, pipelineSlug, buildNumber, job.ID)
+					if err != nil {
+						addError(fmt.Errorf("job %s: %w", job.ID, err))
+						return
+					}
+					rawLogs[i] = log
+				}()
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				list, resp, err := annotations.ListByBuild(ctx, org, pipelineSlug, buildNumber, &buildkite.AnnotationListOptions{
+					ListOptions: buildkite.ListOptions{PerPage: 100},
+				})
+				if err != nil {
+					addError(fmt.Errorf("annotations: %w", err))
+					return
+				}
+				if resp.StatusCode != http.StatusOK {
+					addError(fmt.Errorf("annotations: unexpected status %d", resp.StatusCode))
+					return
+				}
+				rawNotes = list
+			}()
+
+			for i, run := range testEngineRuns {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					executions, resp, err := testExecutions.GetFailedExecutions(ctx, org, run.Suite.Slug, run.ID, &buildkite.FailedExecutionsOptions{})
+					if err != nil {
+						addError(fmt.Errorf("test run %s: %w", run.ID, err))
+						return
+					}
+					if resp.StatusCode != http.StatusOK {
+						addError(fmt.Errorf("test run %s: unexpected status %d", run.ID, resp.StatusCode))
+						return
+					}
+					rawTests[i] = executions
+				}()
+			}
+
+			wg.Wait()
+
+			if len(diagnosis.FailedJobs) > 0 {
+				perJobBudget := logBudget / len(diagnosis.FailedJobs)
+				for i := range diagnosis.FailedJobs {
+					diagnosis.FailedJobs[i].LogExcerpt, diagnosis.FailedJobs[i].LogTruncated = tokens.TailWithinBudget(rawLogs[i], perJobBudget)
+				}
+			}
+
+			for _, annotation := range rawNotes {
+				if annotation.Style != "error" && annotation.Style != "warning" {
+					continue
+				}
+
+				body, err := markdown.FromHTML(annotation.BodyHTML)
+				if err != nil {
+					body = annotation.BodyHTML
+				}
+
+				cost := tokens.EstimateTokens(body)
+				if cost > annotationBudget {
+					diagnosis.Omitted["annotations"]++
+					continue
+				}
+				annotationBudget -= cost
+
+				diagnosis.Annotations = append(diagnosis.Annotations, DiagnosedAnnotation{
+					Context: annotation.Context,
+					Style:   annotation.Style,
+					Body:    body,
+				})
+			}
+
+			for _, executions := range rawTests {
+				for _, execution := range executions {
+					test := DiagnosedTest{
+						TestName:      execution.TestName,
+						Location:      execution.Location,
+						FailureReason: execution.FailureReason,
+						TestURL:       execution.TestURL,
+					}
+
+					cost := tokens.EstimateTokens(test.TestName + " " + test.Location + " " + test.FailureReason)
+					if cost > testBudget {
+						diagnosis.Omitted["failing_tests"]++
+						continue
+					}
+					testBudget -= cost
+
+					diagnosis.FailingTests = append(diagnosis.FailingTests, test)
+				}
+			}
+
+			r, err := json.Marshal(&diagnosis)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal build diagnosis: %w", err)
+			}
+
+			span.SetAttributes(
+				attribute.Int("failed_jobs", len(diagnosis.FailedJobs)),
+				attribute.Int("tokens", tokens.EstimateTokens(string(r))),
+			)
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func fetchProcessedJobLog(ctx context.Context, client JobLogsClient, org, pipelineSlug, buildNumber, jobID string) (string, error) {
+	joblog, resp, err := client.GetJobLog(ctx, org, pipelineSlug, buildNumber, jobID)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return joblogs.Process(joblog)
+}
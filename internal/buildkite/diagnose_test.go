@@ -0,0 +1,125 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type MockJobLogsClient struct {
+	GetJobLogFunc func(ctx context.Context, org, pipelineSlug, buildNumber, jobID string) (buildkite.JobLog, *buildkite.Response, error)
+}
+
+func (m *MockJobLogsClient) GetJobLog(ctx context.Context, org, pipelineSlug, buildNumber, jobID string) (buildkite.JobLog, *buildkite.Response, error) {
+	if m.GetJobLogFunc != nil {
+		return m.GetJobLogFunc(ctx, org, pipelineSlug, buildNumber, jobID)
+	}
+	return buildkite.JobLog{}, nil, nil
+}
+
+var _ JobLogsClient = (*MockJobLogsClient)(nil)
+
+func TestDiagnoseBuild(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	exitStatus := 2
+
+	builds := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			assert.True(opt.IncludeTestEngine)
+			return buildkite.Build{
+					Number: 7,
+					State:  "failed",
+					Jobs: []buildkite.Job{
+						{ID: "job1", Name: "lint", State: "passed"},
+						{ID: "job2", Name: "test", State: "failed", ExitStatus: &exitStatus},
+						{ID: "job3", Name: "deploy", State: "failed"},
+					},
+					TestEngine: &buildkite.TestEngineProperty{
+						Runs: []buildkite.TestEngineRun{
+							{ID: "run1", Suite: buildkite.TestEngineSuite{Slug: "suite"}},
+						},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	jobLogs := &MockJobLogsClient{
+		GetJobLogFunc: func(ctx context.Context, org, pipelineSlug, buildNumber, jobID string) (buildkite.JobLog, *buildkite.Response, error) {
+			if jobID == "job3" {
+				return buildkite.JobLog{}, nil, errors.New("log unavailable")
+			}
+			return buildkite.JobLog{
+					Content: strings.Repeat("noise\n", 200) + "FAIL: TestWidget expected 1 got 2\n",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	annotations := &MockAnnotationsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error) {
+			return []buildkite.Annotation{
+					{Context: "coverage", Style: "info", BodyHTML: "<p>Coverage 80%</p>"},
+					{Context: "junit", Style: "error", BodyHTML: "<p><strong>1 test failed</strong></p>"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	testExecutions := &MockTestExecutionsClient{
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			assert.Equal("suite", slug)
+			assert.Equal("run1", runID)
+			return []buildkite.FailedExecution{
+					{TestName: "TestWidget", Location: "widget_test.go:12", FailureReason: "expected 1 got 2"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := DiagnoseBuild(ctx, builds, jobLogs, annotations, testExecutions)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "7",
+		"max_tokens":    float64(500),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var diagnosis BuildDiagnosis
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &diagnosis))
+
+	assert.Len(diagnosis.FailedJobs, 2)
+	assert.Equal("job2", diagnosis.FailedJobs[0].ID)
+	assert.True(diagnosis.FailedJobs[0].LogTruncated)
+	assert.True(strings.HasSuffix(diagnosis.FailedJobs[0].LogExcerpt, "FAIL: TestWidget expected 1 got 2"))
+	assert.Empty(diagnosis.FailedJobs[1].LogExcerpt)
+
+	assert.Equal([]DiagnosedAnnotation{{Context: "junit", Style: "error", Body: "**1 test failed**"}}, diagnosis.Annotations)
+	assert.Equal([]DiagnosedTest{{TestName: "TestWidget", Location: "widget_test.go:12", FailureReason: "expected 1 got 2"}}, diagnosis.FailingTests)
+	assert.Equal([]string{"job job3: log unavailable"}, diagnosis.Errors)
+}
@@ -0,0 +1,206 @@
+package buildkite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// API error categories, which tell the caller what to do about an error
+const (
+	ErrorUnauthorized   = "unauthorized"
+	ErrorForbidden      = "forbidden"
+	ErrorNotFound       = "not_found"
+	ErrorInvalidRequest = "invalid_request"
+	ErrorRateLimited    = "rate_limited"
+	ErrorServer         = "server_error"
+	ErrorRequestFailed  = "request_failed"
+)
+
+// maxErrorMessage caps how much of a response body which isn't JSON is used as the error message
+const maxErrorMessage = 1000
+
+// APIError is a failed Buildkite API request, classified by its status so tools return errors
+// which say what went wrong and how to fix it. Tools return it as JSON error content.
+type APIError struct {
+	Operation string          `json:"operation"`
+	Status    int             `json:"status,omitempty"`
+	Category  string          `json:"category"`
+	Message   string          `json:"message"`
+	Hint      string          `json:"hint,omitempty"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	RetryAt   *time.Time      `json:"retry_at,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("failed to %s: %s", e.Operation, e.Message)
+	}
+	return fmt.Sprintf("failed to %s: %d %s", e.Operation, e.Status, e.Message)
+}
+
+// newAPIError classifies a failed request from either the error go-buildkite returned, which for
+// non-2xx responses is a *buildkite.ErrorResponse and for rate limited ones a *RateLimitedError, or a
+// response with an unexpected status
+func newAPIError(operation string, resp *buildkite.Response, err error) *APIError {
+	apiErr := &APIError{Operation: operation}
+
+	var (
+		httpResp *http.Response
+		body     []byte
+	)
+	var (
+		errResp     *buildkite.ErrorResponse
+		rateLimited *RateLimitedError
+	)
+	switch {
+	case errors.As(err, &errResp) && errResp.Response != nil:
+		httpResp = errResp.Response
+		body = errResp.RawBody
+	case errors.As(err, &rateLimited):
+		httpResp = rateLimited.Response
+		body = rateLimited.Body
+	case err != nil:
+		apiErr.Category = ErrorRequestFailed
+		apiErr.Message = err.Error()
+		apiErr.Hint = "The request to the Buildkite API didn't complete. Check the server can reach the API and try again."
+		return apiErr
+	case resp != nil && resp.Response != nil:
+		httpResp = resp.Response
+		if httpResp.Body != nil {
+			body, _ = io.ReadAll(httpResp.Body)
+		}
+	}
+
+	if httpResp == nil {
+		apiErr.Category = ErrorRequestFailed
+		apiErr.Message = "no response from the Buildkite API"
+		return apiErr
+	}
+
+	apiErr.Status = httpResp.StatusCode
+	apiErr.Message, apiErr.Details = parseErrorBody(body)
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(httpResp.StatusCode)
+	}
+
+	switch status := httpResp.StatusCode; {
+	case status == http.StatusUnauthorized:
+		apiErr.Category = ErrorUnauthorized
+		apiErr.Hint = "The Buildkite API token is missing, invalid or expired. Check the token the server was started with (BUILDKITE_API_TOKEN)."
+	case status == http.StatusForbidden:
+		apiErr.Category = ErrorForbidden
+		apiErr.Hint = fmt.Sprintf("The API token isn't allowed to %s. Check the token has the scope this needs, which the message may name, and access to the organization.", operation)
+	case status == http.StatusNotFound:
+		apiErr.Category = ErrorNotFound
+		apiErr.Hint = "Check the organization slug, pipeline slug, build number and any IDs are correct. The API also returns not found for organizations the token can't access."
+	case status == http.StatusTooManyRequests:
+		apiErr.Category = ErrorRateLimited
+		apiErr.RetryAt = retryAt(httpResp.Header, time.Now())
+		if apiErr.RetryAt != nil {
+			apiErr.Hint = fmt.Sprintf("The API rate limit was reached. Wait until %s before trying again.", apiErr.RetryAt.Format(time.RFC3339))
+		} else {
+			apiErr.Hint = "The API rate limit was reached. Wait a minute before trying again."
+		}
+	case status >= 500:
+		apiErr.Category = ErrorServer
+		apiErr.Hint = "The Buildkite API failed to handle the request. This is usually temporary, so try again shortly."
+	case status >= 400:
+		apiErr.Category = ErrorInvalidRequest
+		apiErr.Hint = "The API rejected the request. Fix the values the message and details point to before trying again."
+	default:
+		apiErr.Category = ErrorRequestFailed
+	}
+
+	return apiErr
+}
+
+// RateLimitedError is a rate limited API response, returned as an error by the RateLimitTransport
+type RateLimitedError struct {
+	Response *http.Response
+	Body     []byte
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%d %s", e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
+}
+
+// RateLimitTransport returns rate limited responses as a *RateLimitedError. go-buildkite retries
+// rate limited GET requests with backoff for up to 15 minutes and then returns an error without the
+// response, so tools would hang and could never say when to retry. An error from the transport
+// isn't retried, and keeps the response's RateLimit-Reset header for newAPIError.
+type RateLimitTransport struct {
+	Wrapped http.RoundTripper
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Wrapped.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorMessage+1))
+	_ = resp.Body.Close()
+	resp.Body = http.NoBody
+	return nil, &RateLimitedError{Response: resp, Body: body}
+}
+
+// parseErrorBody gets the message and any field errors from an API error, falling back to the body
+// itself when it isn't JSON
+func parseErrorBody(body []byte) (string, json.RawMessage) {
+	var parsed struct {
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		return parsed.Message, parsed.Errors
+	}
+
+	message := strings.TrimSpace(string(body))
+	if len(message) > maxErrorMessage {
+		message = message[:maxErrorMessage] + "..."
+	}
+	return message, nil
+}
+
+// retryAt is when a rate limited request can be retried, from the number of seconds until the rate
+// limit resets or the Retry-After header
+func retryAt(header http.Header, now time.Time) *time.Time {
+	for _, name := range []string{"RateLimit-Reset", "Retry-After"} {
+		seconds, err := strconv.Atoi(header.Get(name))
+		if err == nil && seconds >= 0 {
+			at := now.Add(time.Duration(seconds) * time.Second).UTC().Truncate(time.Second)
+			return &at
+		}
+	}
+	return nil
+}
+
+// apiErrorResult returns a failed request as a tool error with the classified error as JSON content
+func apiErrorResult(operation string, resp *buildkite.Response, err error) *mcp.CallToolResult {
+	return apiErrorToolResult(newAPIError(operation, resp, err))
+}
+
+// toolErrorResult returns an error as a tool error, as JSON content when it's an API error
+func toolErrorResult(err error) *mcp.CallToolResult {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErrorToolResult(apiErr)
+	}
+	return mcp.NewToolResultError(err.Error())
+}
+
+func apiErrorToolResult(apiErr *APIError) *mcp.CallToolResult {
+	r, err := json.Marshal(apiErr)
+	if err != nil {
+		return mcp.NewToolResultError(apiErr.Error())
+	}
+	return mcp.NewToolResultError(string(r))
+}
@@ -0,0 +1,220 @@
+package buildkite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func errorResponse(status int, header http.Header, body string) *buildkite.ErrorResponse {
+	return &buildkite.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: status,
+			Header:     header,
+		},
+		RawBody: []byte(body),
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		category string
+		message  string
+	}{
+		{
+			name:     "unauthorized",
+			err:      errorResponse(http.StatusUnauthorized, nil, `{"message":"Authentication required"}`),
+			status:   http.StatusUnauthorized,
+			category: ErrorUnauthorized,
+			message:  "Authentication required",
+		},
+		{
+			name:     "forbidden",
+			err:      errorResponse(http.StatusForbidden, nil, `{"message":"Your access token doesn't have the read_builds scope"}`),
+			status:   http.StatusForbidden,
+			category: ErrorForbidden,
+			message:  "Your access token doesn't have the read_builds scope",
+		},
+		{
+			name:     "not found",
+			err:      errorResponse(http.StatusNotFound, nil, `{"message":"No pipeline found"}`),
+			status:   http.StatusNotFound,
+			category: ErrorNotFound,
+			message:  "No pipeline found",
+		},
+		{
+			name:     "server error without a body",
+			err:      errorResponse(http.StatusBadGateway, nil, ""),
+			status:   http.StatusBadGateway,
+			category: ErrorServer,
+			message:  "Bad Gateway",
+		},
+		{
+			name:     "body which isn't JSON",
+			err:      errorResponse(http.StatusServiceUnavailable, nil, "<html>down for maintenance</html>\n"),
+			status:   http.StatusServiceUnavailable,
+			category: ErrorServer,
+			message:  "<html>down for maintenance</html>",
+		},
+		{
+			name:     "wrapped error response",
+			err:      fmt.Errorf("listing: %w", errorResponse(http.StatusNotFound, nil, `{"message":"Not Found"}`)),
+			status:   http.StatusNotFound,
+			category: ErrorNotFound,
+			message:  "Not Found",
+		},
+		{
+			name:     "network error",
+			err:      errors.New("dial tcp: connection refused"),
+			category: ErrorRequestFailed,
+			message:  "dial tcp: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			apiErr := newAPIError("get pipeline", nil, tt.err)
+			assert.Equal("get pipeline", apiErr.Operation)
+			assert.Equal(tt.status, apiErr.Status)
+			assert.Equal(tt.category, apiErr.Category)
+			assert.Equal(tt.message, apiErr.Message)
+			assert.NotEmpty(apiErr.Hint)
+		})
+	}
+}
+
+func TestNewAPIErrorInvalidRequestDetails(t *testing.T) {
+	assert := require.New(t)
+
+	apiErr := newAPIError("create pipeline", nil, errorResponse(http.StatusUnprocessableEntity, nil,
+		`{"message":"Validation Failed","errors":[{"field":"name","code":"already_exists"}]}`))
+
+	assert.Equal(ErrorInvalidRequest, apiErr.Category)
+	assert.Equal("Validation Failed", apiErr.Message)
+	assert.JSONEq(`[{"field":"name","code":"already_exists"}]`, string(apiErr.Details))
+	assert.Equal("failed to create pipeline: 422 Validation Failed", apiErr.Error())
+}
+
+func TestNewAPIErrorRateLimited(t *testing.T) {
+	assert := require.New(t)
+
+	header := http.Header{}
+	header.Set("RateLimit-Reset", "30")
+
+	before := time.Now()
+	apiErr := newAPIError("list builds", nil, errorResponse(http.StatusTooManyRequests, header, `{"message":"Too Many Requests"}`))
+
+	assert.Equal(ErrorRateLimited, apiErr.Category)
+	assert.NotNil(apiErr.RetryAt)
+	assert.WithinDuration(before.Add(30*time.Second), *apiErr.RetryAt, 2*time.Second)
+	assert.Contains(apiErr.Hint, apiErr.RetryAt.Format(time.RFC3339))
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	assert := require.New(t)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("RateLimit-Reset", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"You have exceeded your API rate limit"}`))
+	}))
+	defer server.Close()
+
+	client, err := buildkite.NewOpts(
+		buildkite.WithBaseURL(server.URL),
+		buildkite.WithHTTPClient(&http.Client{Transport: &RateLimitTransport{Wrapped: http.DefaultTransport}}),
+	)
+	assert.NoError(err)
+
+	ctx := context.Background()
+	_, handler := GetPipeline(ctx, client.Pipelines)
+
+	before := time.Now()
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+
+	// the rate limited GET isn't retried, and the response's reset time is reported
+	assert.Equal(1, requests)
+
+	var apiErr APIError
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &apiErr))
+	assert.Equal(http.StatusTooManyRequests, apiErr.Status)
+	assert.Equal(ErrorRateLimited, apiErr.Category)
+	assert.Equal("You have exceeded your API rate limit", apiErr.Message)
+	assert.NotNil(apiErr.RetryAt)
+	assert.WithinDuration(before.Add(30*time.Second), *apiErr.RetryAt, 2*time.Second)
+}
+
+func TestNewAPIErrorRateLimitedRetryAfter(t *testing.T) {
+	assert := require.New(t)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("Retry-After", "5")
+	assert.Equal(now.Add(5*time.Second), *retryAt(header, now))
+
+	assert.Nil(retryAt(http.Header{}, now))
+}
+
+func TestNewAPIErrorFromResponse(t *testing.T) {
+	assert := require.New(t)
+
+	resp := &buildkite.Response{
+		Response: &http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Forbidden"}`)),
+		},
+	}
+
+	apiErr := newAPIError("list clusters", resp, nil)
+	assert.Equal(http.StatusForbidden, apiErr.Status)
+	assert.Equal(ErrorForbidden, apiErr.Category)
+	assert.Equal("Forbidden", apiErr.Message)
+	assert.Contains(apiErr.Hint, "list clusters")
+}
+
+func TestNewAPIErrorLongBody(t *testing.T) {
+	assert := require.New(t)
+
+	apiErr := newAPIError("get job log", nil, errorResponse(http.StatusInternalServerError, nil, strings.Repeat("x", 2*maxErrorMessage)))
+	assert.Len(apiErr.Message, maxErrorMessage+len("..."))
+}
+
+func TestToolErrorResult(t *testing.T) {
+	assert := require.New(t)
+
+	result := toolErrorResult(fmt.Errorf("scanning: %w", newAPIError("list test runs", nil, errorResponse(http.StatusNotFound, nil, ""))))
+	assert.True(result.IsError)
+
+	var apiErr APIError
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &apiErr))
+	assert.Equal("list test runs", apiErr.Operation)
+	assert.Equal(ErrorNotFound, apiErr.Category)
+
+	result = toolErrorResult(errors.New("runs must be between 1 and 100"))
+	assert.True(result.IsError)
+	assert.Equal("runs must be between 1 and 100", getTextResult(t, result).Text)
+}
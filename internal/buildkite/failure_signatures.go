@@ -0,0 +1,118 @@
+package buildkite
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/buildkite/go-buildkite/v4"
+)
+
+const (
+	// signatureLines caps how many lines of the expanded failure and of the backtrace make up a signature
+	signatureLines = 5
+	// maxSignatureTestNames caps how many test names are listed per signature
+	maxSignatureTestNames = 10
+)
+
+// FailureSignatureGroup is a set of failed executions which failed in the same way
+type FailureSignatureGroup struct {
+	Signature string                    `json:"signature"`
+	Count     int                       `json:"count"`
+	TestNames []string                  `json:"test_names"`
+	Example   buildkite.FailedExecution `json:"example"`
+}
+
+var (
+	uuidRegexp    = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	addressRegexp = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`)
+	hexIDRegexp   = regexp.MustCompile(`(?i)\b[0-9a-f]{7,}\b`)
+	numberRegexp  = regexp.MustCompile(`[0-9]+`)
+	spaceRegexp   = regexp.MustCompile(`\s+`)
+)
+
+// normalizeFailureLine replaces the parts of a failure which differ between otherwise identical
+// failures. Identifiers are replaced before the digits inside them.
+func normalizeFailureLine(line string) string {
+	line = uuidRegexp.ReplaceAllString(line, "<uuid>")
+	line = addressRegexp.ReplaceAllString(line, "<addr>")
+	line = hexIDRegexp.ReplaceAllStringFunc(line, func(id string) string {
+		// long hex strings with letters are ids and digests, but long plain numbers are left to the next step
+		if strings.IndexFunc(id, unicode.IsLetter) == -1 {
+			return id
+		}
+		return "<id>"
+	})
+	line = numberRegexp.ReplaceAllString(line, "N")
+	return strings.TrimSpace(spaceRegexp.ReplaceAllString(line, " "))
+}
+
+// failureSignature normalises the reason, expanded message and top of the backtrace of a failure so
+// failures with the same cause share a signature
+func failureSignature(execution buildkite.FailedExecution) string {
+	var lines []string
+	if execution.FailureReason != "" {
+		lines = append(lines, execution.FailureReason)
+	}
+	for _, expanded := range execution.FailureExpanded {
+		lines = append(lines, firstLines(expanded.Expanded, signatureLines)...)
+		lines = append(lines, firstLines(expanded.Backtrace, signatureLines)...)
+	}
+
+	for i, line := range lines {
+		lines[i] = normalizeFailureLine(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// firstLines returns up to n of the lines which aren't blank
+func firstLines(lines []string, n int) []string {
+	var result []string
+	for _, line := range lines {
+		if len(result) == n {
+			break
+		}
+		if strings.TrimSpace(line) != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// groupBySignature clusters failed executions by their failure signature, most common first
+func groupBySignature(executions []buildkite.FailedExecution) []FailureSignatureGroup {
+	groups := make(map[string]*FailureSignatureGroup)
+	var order []string
+
+	for _, execution := range executions {
+		signature := failureSignature(execution)
+		group, ok := groups[signature]
+		if !ok {
+			group = &FailureSignatureGroup{
+				Signature: signature,
+				TestNames: make([]string, 0),
+				Example:   execution,
+			}
+			groups[signature] = group
+			order = append(order, signature)
+		}
+
+		group.Count++
+		if len(group.TestNames) < maxSignatureTestNames && !slices.Contains(group.TestNames, execution.TestName) {
+			group.TestNames = append(group.TestNames, execution.TestName)
+		}
+	}
+
+	result := make([]FailureSignatureGroup, 0, len(order))
+	for _, signature := range order {
+		result = append(result, *groups[signature])
+	}
+	slices.SortStableFunc(result, func(a, b FailureSignatureGroup) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	return result
+}
@@ -0,0 +1,26 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFailureLine(t *testing.T) {
+	assert := require.New(t)
+
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{"app/models/user.rb:42:in `save!'", "app/models/user.rb:N:in `save!'"},
+		{"panic: runtime error at 0xc000123abc", "panic: runtime error at <addr>"},
+		{"job 7c9e6679-7425-40de-944b-e07fc1f90ae7 failed", "job <uuid> failed"},
+		{"commit deadbeef1234 not found", "commit <id> not found"},
+		{"  expected   3 got 4  ", "expected N got N"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(tt.expected, normalizeFailureLine(tt.line), tt.line)
+	}
+}
@@ -0,0 +1,312 @@
+package buildkite
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultFlakyTestRuns is how many of a suite's most recent runs are analysed by default
+	defaultFlakyTestRuns = 20
+	// maxFlakyTestRuns caps how many runs can be analysed at once
+	maxFlakyTestRuns = 100
+	// failedExecutionsConcurrency limits how many runs' failed executions are fetched at once
+	failedExecutionsConcurrency = 5
+)
+
+// FlakyTest is a test which failed in at least one of the analysed runs. It's flaky when it also
+// passed in another run of a commit it failed on.
+type FlakyTest struct {
+	TestID             string     `json:"test_id"`
+	Name               string     `json:"name"`
+	Location           string     `json:"location,omitempty"`
+	Flaky              bool       `json:"flaky"`
+	FlakyCommits       []string   `json:"flaky_commits,omitempty"`
+	Failures           int        `json:"failures"`
+	FailureRatePercent float64    `json:"failure_rate_percent"`
+	FirstSeen          *time.Time `json:"first_seen,omitempty"`
+	LastSeen           *time.Time `json:"last_seen,omitempty"`
+	FailureReason      string     `json:"failure_reason,omitempty"`
+	TestURL            string     `json:"test_url,omitempty"`
+}
+
+// FlakyTestsResult lists the tests which failed across a suite's recent runs, flaky tests first.
+// Runs whose failed executions couldn't be fetched are skipped rather than analysed.
+type FlakyTestsResult struct {
+	RunsAnalyzed int                                  `json:"runs_analyzed"`
+	RunsSkipped  int                                  `json:"runs_skipped,omitempty"`
+	FailedRuns   int                                  `json:"failed_runs"`
+	Tests        ClientSidePaginatedResult[FlakyTest] `json:"tests"`
+	Errors       []string                             `json:"errors,omitempty"`
+}
+
+func FindFlakyTests(ctx context.Context, client TestRunsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("find_flaky_tests",
+			mcp.WithDescription("Find flaky tests in a Buildkite Test Engine suite by walking its most recent runs and aggregating their failed executions by test. A test is flagged as flaky when it failed in one run and not in another finished run of the same commit. Returns each failing test's failure rate across the runs, when it first and last failed, and its most common failure reason, with flaky tests first"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the test suite"),
+			),
+			mcp.WithString("test_suite_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the test suite"),
+			),
+			mcp.WithNumber("runs",
+				mcp.Description("Number of the most recent runs to analyse (default 20)"),
+				mcp.Min(1),
+				mcp.Max(maxFlakyTestRuns),
+			),
+			mcp.WithBoolean("only_flaky",
+				mcp.Description("Only return tests flagged as flaky, leaving out tests which failed consistently (default false)"),
+			),
+			withClientSidePagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Find Flaky Tests",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.FindFlakyTests")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			testSuiteSlug, err := request.RequireString("test_suite_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			runs := request.GetInt("runs", defaultFlakyTestRuns)
+			if runs < 1 || runs > maxFlakyTestRuns {
+				return mcp.NewToolResultError(fmt.Sprintf("runs must be between 1 and %d", maxFlakyTestRuns)), nil
+			}
+
+			onlyFlaky := request.GetBool("only_flaky", false)
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("test_suite_slug", testSuiteSlug),
+				attribute.Int("runs", runs),
+				attribute.Bool("only_flaky", onlyFlaky),
+			)
+
+			testRuns, err := listTestRunsUpTo(ctx, client, org, testSuiteSlug, runs)
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			// runs which are still going may not have reported all of their failures yet
+			finished := make([]buildkite.TestRun, 0, len(testRuns))
+			for _, run := range testRuns {
+				if run.State == "" || run.State == "finished" {
+					finished = append(finished, run)
+				}
+			}
+
+			analyzed, failures, errs := getFailedExecutions(ctx, client, org, testSuiteSlug, finished)
+
+			tests := aggregateFlakyTests(analyzed, failures)
+			if onlyFlaky {
+				tests = slices.DeleteFunc(tests, func(test FlakyTest) bool { return !test.Flaky })
+			}
+
+			result := FlakyTestsResult{
+				RunsAnalyzed: len(analyzed),
+				RunsSkipped:  len(finished) - len(analyzed),
+				Tests:        applyClientSidePagination(tests, getClientSidePaginationParams(request)),
+				Errors:       errs,
+			}
+			for _, run := range analyzed {
+				if run.Result == "failed" {
+					result.FailedRuns++
+				}
+			}
+
+			span.SetAttributes(attribute.Int("tests", result.Tests.Total))
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal flaky tests: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// getFailedExecutions fetches the failed executions of each failed run. Runs whose failed executions
+// couldn't be fetched are left out, as there's no telling which tests passed in them, so the runs
+// returned are the ones the failures are indexed by.
+func getFailedExecutions(ctx context.Context, client TestRunsClient, org, testSuiteSlug string, runs []buildkite.TestRun) ([]buildkite.TestRun, [][]buildkite.FailedExecution, []string) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []string
+		failures = make([][]buildkite.FailedExecution, len(runs))
+		fetched  = make([]bool, len(runs))
+		limit    = make(chan struct{}, failedExecutionsConcurrency)
+	)
+
+	for i, run := range runs {
+		if run.Result != "failed" {
+			fetched[i] = true
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			executions, resp, err := client.GetFailedExecutions(ctx, org, testSuiteSlug, run.ID, &buildkite.FailedExecutionsOptions{})
+			if err != nil {
+				err = newAPIError("get failed executions", resp, err)
+			} else if resp.StatusCode != http.StatusOK {
+				err = newAPIError("get failed executions", resp, nil)
+			}
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Sprintf("run %s: %s", run.ID, err))
+				return
+			}
+			failures[i] = executions
+			fetched[i] = true
+		}()
+	}
+	wg.Wait()
+
+	analyzed := make([]buildkite.TestRun, 0, len(runs))
+	analyzedFailures := make([][]buildkite.FailedExecution, 0, len(runs))
+	for i, run := range runs {
+		if fetched[i] {
+			analyzed = append(analyzed, run)
+			analyzedFailures = append(analyzedFailures, failures[i])
+		}
+	}
+
+	return analyzed, analyzedFailures, errs
+}
+
+// aggregateFlakyTests groups failed executions by test. A failed test is taken to have passed in any
+// other finished run of the same commit which it didn't fail in.
+func aggregateFlakyTests(runs []buildkite.TestRun, failures [][]buildkite.FailedExecution) []FlakyTest {
+	runsByCommit := make(map[string]int)
+	for _, run := range runs {
+		if run.CommitSHA != "" {
+			runsByCommit[run.CommitSHA]++
+		}
+	}
+
+	type aggregate struct {
+		test          FlakyTest
+		failedCommits map[string]int
+		reasons       map[string]int
+	}
+
+	byTest := make(map[string]*aggregate)
+	var order []string
+
+	for i, run := range runs {
+		seen := make(map[string]bool)
+		for _, execution := range failures[i] {
+			if seen[execution.TestID] {
+				continue
+			}
+			seen[execution.TestID] = true
+
+			agg, ok := byTest[execution.TestID]
+			if !ok {
+				agg = &aggregate{
+					test: FlakyTest{
+						TestID:   execution.TestID,
+						Name:     execution.TestName,
+						Location: execution.Location,
+						TestURL:  execution.TestURL,
+					},
+					failedCommits: make(map[string]int),
+					reasons:       make(map[string]int),
+				}
+				byTest[execution.TestID] = agg
+				order = append(order, execution.TestID)
+			}
+
+			agg.test.Failures++
+			if run.CommitSHA != "" {
+				agg.failedCommits[run.CommitSHA]++
+			}
+			if execution.FailureReason != "" {
+				agg.reasons[execution.FailureReason]++
+			}
+
+			at := execution.CreatedAt
+			if at == nil {
+				at = run.CreatedAt
+			}
+			if at != nil {
+				if agg.test.FirstSeen == nil || at.Before(*agg.test.FirstSeen) {
+					agg.test.FirstSeen = &at.Time
+				}
+				if agg.test.LastSeen == nil || at.After(*agg.test.LastSeen) {
+					agg.test.LastSeen = &at.Time
+				}
+			}
+		}
+	}
+
+	tests := make([]FlakyTest, 0, len(order))
+	for _, id := range order {
+		agg := byTest[id]
+		test := agg.test
+
+		for commit, failed := range agg.failedCommits {
+			if runsByCommit[commit] > failed {
+				test.FlakyCommits = append(test.FlakyCommits, commit)
+			}
+		}
+		slices.Sort(test.FlakyCommits)
+		test.Flaky = len(test.FlakyCommits) > 0
+
+		test.FailureRatePercent = percent(test.Failures, len(runs))
+		test.FailureReason = mostCommon(agg.reasons)
+
+		tests = append(tests, test)
+	}
+
+	slices.SortStableFunc(tests, func(a, b FlakyTest) int {
+		if a.Flaky != b.Flaky {
+			if a.Flaky {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.Failures, a.Failures)
+	})
+
+	return tests
+}
+
+// mostCommon returns the most frequent value, breaking ties alphabetically so results are stable
+func mostCommon(counts map[string]int) string {
+	var best string
+	for value, count := range counts {
+		if count > counts[best] || (count == counts[best] && value < best) {
+			best = value
+		}
+	}
+	return best
+}
@@ -0,0 +1,164 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindFlakyTests(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	day := func(n int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: time.Date(2025, 6, n, 0, 0, 0, 0, time.UTC)}
+	}
+
+	runs := []buildkite.TestRun{
+		{ID: "run5", CommitSHA: "ccc", State: "running", CreatedAt: day(5)},
+		{ID: "run4", CommitSHA: "bbb", State: "finished", Result: "failed", CreatedAt: day(4)},
+		{ID: "run3", CommitSHA: "bbb", State: "finished", Result: "failed", CreatedAt: day(3)},
+		{ID: "run2", CommitSHA: "aaa", State: "finished", Result: "passed", CreatedAt: day(2)},
+		{ID: "run1", CommitSHA: "aaa", State: "finished", Result: "failed", CreatedAt: day(1)},
+	}
+
+	flaky := func(reason string) buildkite.FailedExecution {
+		return buildkite.FailedExecution{TestID: "flaky", TestName: "it sometimes works", Location: "spec/a_spec.rb:10", FailureReason: reason}
+	}
+	broken := buildkite.FailedExecution{TestID: "broken", TestName: "it never works", FailureReason: "boom"}
+
+	failures := map[string][]buildkite.FailedExecution{
+		"run4": {broken},
+		"run3": {broken, flaky("timeout")},
+		"run1": {flaky("timeout"), flaky("connection refused")},
+	}
+
+	client := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			page, nextPage := runs[:3], 2
+			if opt.Page == 2 {
+				page, nextPage = runs[3:], 0
+			}
+			return page, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: nextPage,
+				}, nil
+		},
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			return failures[runID], &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := FindFlakyTests(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var flakyTests FlakyTestsResult
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &flakyTests))
+	assert.Empty(flakyTests.Errors)
+	assert.Equal(4, flakyTests.RunsAnalyzed)
+	assert.Equal(3, flakyTests.FailedRuns)
+	assert.Equal(2, flakyTests.Tests.Total)
+
+	first := flakyTests.Tests.Items[0]
+	assert.Equal("flaky", first.TestID)
+	assert.True(first.Flaky)
+	assert.Equal([]string{"aaa", "bbb"}, first.FlakyCommits)
+	assert.Equal(2, first.Failures)
+	assert.Equal(50.0, first.FailureRatePercent)
+	assert.Equal("timeout", first.FailureReason)
+	assert.Equal(day(1).Time, *first.FirstSeen)
+	assert.Equal(day(3).Time, *first.LastSeen)
+
+	second := flakyTests.Tests.Items[1]
+	assert.Equal("broken", second.TestID)
+	assert.False(second.Flaky)
+	assert.Equal(2, second.Failures)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"only_flaky":      true,
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &flakyTests))
+	assert.Equal(1, flakyTests.Tests.Total)
+}
+
+func TestFindFlakyTests_FailedExecutionsError(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	runs := []buildkite.TestRun{
+		{ID: "run3", CommitSHA: "bbb", State: "finished", Result: "failed"},
+		{ID: "run2", CommitSHA: "bbb", State: "finished", Result: "failed"},
+		{ID: "run1", CommitSHA: "aaa", State: "finished", Result: "passed"},
+	}
+
+	client := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			return runs, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			if runID == "run3" {
+				return nil, &buildkite.Response{
+						Response: &http.Response{
+							StatusCode: 500,
+						},
+					}, nil
+			}
+			return []buildkite.FailedExecution{
+					{TestID: "test", TestName: "it fails"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	_, handler := FindFlakyTests(ctx, client)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	// run3's results are unknown, so it's neither a pass on commit bbb nor part of the failure rate
+	var flakyTests FlakyTestsResult
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &flakyTests))
+	assert.Equal(2, flakyTests.RunsAnalyzed)
+	assert.Equal(1, flakyTests.RunsSkipped)
+	assert.Len(flakyTests.Errors, 1)
+	assert.Contains(flakyTests.Errors[0], "run run3: failed to get failed executions: 500")
+	assert.Equal(1, flakyTests.Tests.Total)
+	assert.False(flakyTests.Tests.Items[0].Flaky)
+	assert.Equal(50.0, flakyTests.Tests.Items[0].FailureRatePercent)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/joblogs"
@@ -96,15 +95,11 @@ func GetJobs(ctx context.Context, client BuildsClient) (tool mcp.Tool, handler s
 
 			build, resp, err := client.Get(ctx, org, pipelineSlug, buildNumber, &buildkite.BuildGetOptions{})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get build", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get build: %s", string(body))), nil
+				return apiErrorResult("get build", resp, nil), nil
 			}
 
 			jobs := build.Jobs
@@ -200,15 +195,11 @@ func GetJobLogs(ctx context.Context, client *buildkite.Client) (tool mcp.Tool, h
 
 			joblog, resp, err := client.Jobs.GetJobLog(ctx, org, pipelineSlug, buildNumber, jobUUID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get job log", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
+				return apiErrorResult("get job log", resp, nil), nil
 			}
 
 			// the default logs that come from the API can be pretty dense with ANSI codes or HTML
@@ -0,0 +1,175 @@
+package junit
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNotJUnit is returned when an XML document is not a JUnit report, such as a Cobertura coverage file
+var ErrNotJUnit = errors.New("not a junit report")
+
+// Status is the outcome of a single test case
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// TestCase is a single test case from a JUnit report, flattened out of its suite
+type TestCase struct {
+	Suite     string  `json:"suite,omitempty"`
+	ClassName string  `json:"classname,omitempty"`
+	Name      string  `json:"name"`
+	File      string  `json:"file,omitempty"`
+	Line      string  `json:"line,omitempty"`
+	Duration  float64 `json:"duration,omitempty"`
+	Status    Status  `json:"status"`
+	Message   string  `json:"message,omitempty"`
+	Type      string  `json:"type,omitempty"`
+	Details   string  `json:"details,omitempty"`
+}
+
+// Totals counts the test cases in one or more reports by status
+type Totals struct {
+	Tests    int     `json:"tests"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Errors   int     `json:"errors"`
+	Skipped  int     `json:"skipped"`
+	Duration float64 `json:"duration"`
+}
+
+// Add accumulates other into t
+func (t *Totals) Add(other Totals) {
+	t.Tests += other.Tests
+	t.Passed += other.Passed
+	t.Failed += other.Failed
+	t.Errors += other.Errors
+	t.Skipped += other.Skipped
+	t.Duration += other.Duration
+}
+
+type suite struct {
+	XMLName xml.Name
+	Name    string     `xml:"name,attr"`
+	Suites  []suite    `xml:"testsuite"`
+	Cases   []testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	File      string   `xml:"file,attr"`
+	Line      string   `xml:"line,attr"`
+	Time      string   `xml:"time,attr"`
+	Failures  []result `xml:"failure"`
+	Errors    []result `xml:"error"`
+	Skipped   *result  `xml:"skipped"`
+}
+
+type result struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// Parse reads a JUnit XML report, with either a <testsuites> or <testsuite> root, and returns
+// every test case it contains. Suite level counts are ignored in favour of counting the cases,
+// as many reporters leave them out or get them wrong.
+func Parse(r io.Reader) ([]TestCase, error) {
+	var root suite
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse junit xml: %w", err)
+	}
+
+	if root.XMLName.Local != "testsuites" && root.XMLName.Local != "testsuite" {
+		return nil, ErrNotJUnit
+	}
+
+	cases := make([]TestCase, 0)
+	flatten(&cases, root, "")
+	return cases, nil
+}
+
+func flatten(cases *[]TestCase, s suite, suiteName string) {
+	if s.XMLName.Local == "testsuite" && s.Name != "" {
+		suiteName = s.Name
+	}
+
+	for _, tc := range s.Cases {
+		*cases = append(*cases, convert(tc, suiteName))
+	}
+
+	for _, child := range s.Suites {
+		flatten(cases, child, suiteName)
+	}
+}
+
+func convert(tc testCase, suiteName string) TestCase {
+	c := TestCase{
+		Suite:     suiteName,
+		ClassName: tc.ClassName,
+		Name:      tc.Name,
+		File:      tc.File,
+		Line:      tc.Line,
+		Duration:  parseDuration(tc.Time),
+		Status:    StatusPassed,
+	}
+
+	var outcome *result
+	switch {
+	case len(tc.Errors) > 0:
+		c.Status = StatusError
+		outcome = &tc.Errors[0]
+	case len(tc.Failures) > 0:
+		c.Status = StatusFailed
+		outcome = &tc.Failures[0]
+	case tc.Skipped != nil:
+		c.Status = StatusSkipped
+		outcome = tc.Skipped
+	}
+
+	if outcome != nil {
+		c.Message = strings.TrimSpace(outcome.Message)
+		c.Type = outcome.Type
+		c.Details = strings.TrimSpace(outcome.Body)
+	}
+
+	return c
+}
+
+// parseDuration reads a time attribute in seconds, tolerating thousands separators
+func parseDuration(value string) float64 {
+	d, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Summarize counts cases by status
+func Summarize(cases []TestCase) Totals {
+	var totals Totals
+	for _, c := range cases {
+		totals.Tests++
+		totals.Duration += c.Duration
+		switch c.Status {
+		case StatusPassed:
+			totals.Passed++
+		case StatusFailed:
+			totals.Failed++
+		case StatusError:
+			totals.Errors++
+		case StatusSkipped:
+			totals.Skipped++
+		}
+	}
+	return totals
+}
@@ -0,0 +1,74 @@
+package junit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const report = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rspec">
+  <testsuite name="models" tests="3" failures="1">
+    <testcase classname="spec.models.user_spec" name="validates email" file="spec/models/user_spec.rb" line="12" time="0.25">
+      <failure message="expected true, got false" type="RSpec::Expectations::ExpectationNotMetError">Failure/Error: expect(user).to be_valid
+  ./spec/models/user_spec.rb:13</failure>
+    </testcase>
+    <testcase classname="spec.models.user_spec" name="has a name" time="1,000.5"/>
+    <testcase classname="spec.models.user_spec" name="is pending">
+      <skipped message="not yet implemented"/>
+    </testcase>
+  </testsuite>
+  <testsuite name="api">
+    <testsuite name="api.v2">
+      <testcase classname="api" name="returns 500">
+        <error message="boom" type="RuntimeError">stack</error>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+func TestParse(t *testing.T) {
+	assert := require.New(t)
+
+	cases, err := Parse(strings.NewReader(report))
+	assert.NoError(err)
+	assert.Len(cases, 4)
+
+	assert.Equal(TestCase{
+		Suite:     "models",
+		ClassName: "spec.models.user_spec",
+		Name:      "validates email",
+		File:      "spec/models/user_spec.rb",
+		Line:      "12",
+		Duration:  0.25,
+		Status:    StatusFailed,
+		Message:   "expected true, got false",
+		Type:      "RSpec::Expectations::ExpectationNotMetError",
+		Details:   "Failure/Error: expect(user).to be_valid\n  ./spec/models/user_spec.rb:13",
+	}, cases[0])
+	assert.Equal(1000.5, cases[1].Duration)
+	assert.Equal(StatusSkipped, cases[2].Status)
+	assert.Equal(StatusError, cases[3].Status)
+	assert.Equal("api.v2", cases[3].Suite)
+
+	assert.Equal(Totals{Tests: 4, Passed: 1, Failed: 1, Errors: 1, Skipped: 1, Duration: 1000.75}, Summarize(cases))
+}
+
+func TestParseSingleSuite(t *testing.T) {
+	assert := require.New(t)
+
+	cases, err := Parse(strings.NewReader(`<testsuite name="go"><testcase classname="pkg" name="TestA"/></testsuite>`))
+	assert.NoError(err)
+	assert.Equal([]TestCase{{Suite: "go", ClassName: "pkg", Name: "TestA", Status: StatusPassed}}, cases)
+}
+
+func TestParseNotJUnit(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := Parse(strings.NewReader(`<coverage line-rate="0.8"><packages/></coverage>`))
+	assert.ErrorIs(err, ErrNotJUnit)
+
+	_, err = Parse(strings.NewReader(`not xml`))
+	assert.Error(err)
+}
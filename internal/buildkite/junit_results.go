@@ -0,0 +1,240 @@
+package buildkite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/junit"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultStackLines is how much of each failure's stack trace is returned by default
+	defaultStackLines = 20
+	// maxJUnitArtifacts caps how many XML artifacts are downloaded for a single build
+	maxJUnitArtifacts = 50
+)
+
+// JUnitFile summarizes a single JUnit artifact
+type JUnitFile struct {
+	Path   string       `json:"path"`
+	JobID  string       `json:"job_id,omitempty"`
+	Totals junit.Totals `json:"totals"`
+}
+
+// JUnitResults combines the JUnit artifacts of a build, with failures shaped like get_failed_executions
+type JUnitResults struct {
+	Totals    junit.Totals                                         `json:"totals"`
+	Files     []JUnitFile                                          `json:"files"`
+	Failures  ClientSidePaginatedResult[buildkite.FailedExecution] `json:"failures"`
+	Omitted   int                                                  `json:"omitted_files,omitempty"`
+	Truncated bool                                                 `json:"truncated,omitempty"`
+	Errors    []string                                             `json:"errors,omitempty"`
+}
+
+func GetJUnitResults(ctx context.Context, client ArtifactsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_junit_results",
+			mcp.WithDescription("Find the JUnit XML artifacts uploaded by a build, parse them and return test totals along with the failing test cases, their messages and truncated stack traces. Useful for suites which don't report to Test Engine"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("build_number",
+				mcp.Required(),
+				mcp.Description("The build number"),
+			),
+			mcp.WithString("path_pattern",
+				mcp.Description("Only parse artifacts whose path matches this glob, e.g. 'reports/*.xml' (default any path ending in .xml)"),
+			),
+			mcp.WithNumber("max_stack_lines",
+				mcp.Description("Maximum number of stack trace lines returned per failure (default 20, 0 to omit)"),
+				mcp.Min(0),
+			),
+			withClientSidePagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get JUnit Results",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetJUnitResults")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			buildNumber, err := request.RequireString("build_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pathPattern := request.GetString("path_pattern", "")
+			if _, err := path.Match(pathPattern, ""); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid path_pattern: %s", err)), nil
+			}
+
+			maxStackLines := request.GetInt("max_stack_lines", defaultStackLines)
+			paginationParams := getClientSidePaginationParams(request)
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+				attribute.String("path_pattern", pathPattern),
+				attribute.Int("page", paginationParams.Page),
+				attribute.Int("per_page", paginationParams.PerPage),
+			)
+
+			artifacts, truncated, err := listAllArtifacts(ctx, client, org, pipelineSlug, buildNumber, "")
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			var candidates []buildkite.Artifact
+			for _, artifact := range artifacts {
+				if isJUnitCandidate(artifact, pathPattern) {
+					candidates = append(candidates, artifact)
+				}
+			}
+
+			result := JUnitResults{
+				Files:     make([]JUnitFile, 0),
+				Truncated: truncated,
+			}
+			if len(candidates) > maxJUnitArtifacts {
+				result.Omitted = len(candidates) - maxJUnitArtifacts
+				candidates = candidates[:maxJUnitArtifacts]
+			}
+
+			contents, errs := downloadArtifacts(ctx, client, candidates)
+			result.Errors = errs
+
+			parsed := make([][]junit.TestCase, len(candidates))
+			for i, content := range contents {
+				if content == nil {
+					continue
+				}
+
+				cases, err := junit.Parse(bytes.NewReader(content))
+				if errors.Is(err, junit.ErrNotJUnit) {
+					continue
+				}
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", candidates[i].Path, err))
+					continue
+				}
+				parsed[i] = cases
+			}
+
+			failures := make([]buildkite.FailedExecution, 0)
+			for i, cases := range parsed {
+				if cases == nil {
+					continue
+				}
+
+				totals := junit.Summarize(cases)
+				result.Totals.Add(totals)
+				result.Files = append(result.Files, JUnitFile{
+					Path:   candidates[i].Path,
+					JobID:  candidates[i].JobID,
+					Totals: totals,
+				})
+
+				for _, c := range cases {
+					if c.Status == junit.StatusFailed || c.Status == junit.StatusError {
+						failures = append(failures, junitFailedExecution(c, maxStackLines))
+					}
+				}
+			}
+
+			result.Failures = applyClientSidePagination(failures, paginationParams)
+
+			span.SetAttributes(
+				attribute.Int("files", len(result.Files)),
+				attribute.Int("failures", len(failures)),
+			)
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal junit results: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func isJUnitCandidate(artifact buildkite.Artifact, pathPattern string) bool {
+	if pathPattern != "" {
+		matched, _ := path.Match(pathPattern, artifact.Path)
+		return matched
+	}
+	return strings.HasSuffix(strings.ToLower(artifact.Path), ".xml")
+}
+
+// junitFailedExecution converts a failing JUnit test case into the shape used by Test Engine
+func junitFailedExecution(c junit.TestCase, maxStackLines int) buildkite.FailedExecution {
+	execution := buildkite.FailedExecution{
+		TestName:      c.Name,
+		Location:      c.ClassName,
+		FailureReason: c.Message,
+		Duration:      c.Duration,
+	}
+
+	if c.ClassName != "" {
+		execution.TestName = c.ClassName + "." + c.Name
+	}
+
+	if c.File != "" {
+		execution.Location = c.File
+		if c.Line != "" {
+			execution.Location += ":" + c.Line
+		}
+	}
+
+	lines := nonBlankLines(c.Details)
+	if execution.FailureReason == "" && len(lines) > 0 {
+		execution.FailureReason = strings.TrimSpace(lines[0])
+	}
+
+	if maxStackLines > 0 && len(lines) > 0 {
+		expanded := buildkite.FailureExpanded{Backtrace: lines}
+		if len(lines) > maxStackLines {
+			expanded.Backtrace = append(lines[:maxStackLines:maxStackLines], fmt.Sprintf("... %d more lines", len(lines)-maxStackLines))
+		}
+		if c.Type != "" {
+			expanded.Expanded = []string{c.Type}
+		}
+		execution.FailureExpanded = []buildkite.FailureExpanded{expanded}
+	}
+
+	return execution
+}
+
+func nonBlankLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return lines
+}
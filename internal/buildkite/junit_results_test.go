@@ -0,0 +1,140 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJUnitResults(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	stack := make([]string, 30)
+	for i := range stack {
+		stack[i] = fmt.Sprintf("  at frame%d", i)
+	}
+
+	contents := map[string]string{
+		"https://example.com/junit-1.xml": `<testsuite name="unit">
+			<testcase classname="pkg" name="TestPass" time="0.5"/>
+			<testcase classname="pkg" name="TestFail" file="pkg/a_test.go" line="10" time="1.5">
+				<failure message="expected 1 got 2" type="assertion">` + strings.Join(stack, "\n") + `</failure>
+			</testcase>
+		</testsuite>`,
+		"https://example.com/junit-2.xml": `<testsuites><testsuite name="integration"><testcase name="TestSkip"><skipped/></testcase></testsuite></testsuites>`,
+		"https://example.com/coverage.xml": `<coverage line-rate="0.5"/>`,
+	}
+
+	client := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			return []buildkite.Artifact{
+					{JobID: "job1", Path: "reports/junit-1.xml", DownloadURL: "https://example.com/junit-1.xml"},
+					{JobID: "job2", Path: "reports/junit-2.xml", DownloadURL: "https://example.com/junit-2.xml"},
+					{JobID: "job1", Path: "coverage.xml", DownloadURL: "https://example.com/coverage.xml"},
+					{JobID: "job1", Path: "build.log", DownloadURL: "https://example.com/build.log"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			content, ok := contents[url]
+			if !ok {
+				return nil, fmt.Errorf("unexpected download of %s", url)
+			}
+			_, err := writer.Write([]byte(content))
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, err
+		},
+	}
+
+	tool, handler := GetJUnitResults(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"pipeline_slug":   "pipeline",
+		"build_number":    "1",
+		"max_stack_lines": float64(5),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var results JUnitResults
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &results))
+
+	assert.Equal(3, results.Totals.Tests)
+	assert.Equal(1, results.Totals.Passed)
+	assert.Equal(1, results.Totals.Failed)
+	assert.Equal(1, results.Totals.Skipped)
+	assert.Len(results.Files, 2)
+	assert.Equal("reports/junit-1.xml", results.Files[0].Path)
+	assert.Equal("job1", results.Files[0].JobID)
+	assert.Empty(results.Errors)
+
+	assert.Equal(1, results.Failures.Total)
+	failure := results.Failures.Items[0]
+	assert.Equal("pkg.TestFail", failure.TestName)
+	assert.Equal("pkg/a_test.go:10", failure.Location)
+	assert.Equal("expected 1 got 2", failure.FailureReason)
+	assert.Equal(1.5, failure.Duration)
+	assert.Len(failure.FailureExpanded, 1)
+	assert.Equal([]string{"at frame0", "  at frame1", "  at frame2", "  at frame3", "  at frame4", "... 25 more lines"}, failure.FailureExpanded[0].Backtrace)
+	assert.Equal([]string{"assertion"}, failure.FailureExpanded[0].Expanded)
+}
+
+func TestGetJUnitResults_PathPattern(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var downloaded []string
+	client := &MockArtifactsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.ArtifactListOptions) ([]buildkite.Artifact, *buildkite.Response, error) {
+			return []buildkite.Artifact{
+					{Path: "reports/junit.xml", DownloadURL: "https://example.com/junit.xml"},
+					{Path: "pom.xml", DownloadURL: "https://example.com/pom.xml"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		DownloadArtifactByURLFunc: func(ctx context.Context, url string, writer io.Writer) (*buildkite.Response, error) {
+			downloaded = append(downloaded, url)
+			_, err := writer.Write([]byte(`<testsuite/>`))
+			return &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, err
+		},
+	}
+
+	_, handler := GetJUnitResults(ctx, client)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "1",
+		"path_pattern":  "reports/*.xml",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.False(result.IsError)
+	assert.Equal([]string{"https://example.com/junit.xml"}, downloaded)
+}
@@ -0,0 +1,244 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	whitespaceRegexp = regexp.MustCompile(`\s+`)
+	blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+)
+
+// FromHTML converts rendered HTML, such as the body of a build annotation, into
+// compact Markdown. Headings, emphasis, links, lists, tables, quotes and code
+// blocks are preserved while presentational markup is dropped to save tokens.
+func FromHTML(input string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(input))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	var b strings.Builder
+	renderChildren(&b, doc)
+
+	return tidy(b.String()), nil
+}
+
+func renderChildren(b *strings.Builder, n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		render(b, child)
+	}
+}
+
+func render(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		writeText(b, whitespaceRegexp.ReplaceAllString(n.Data, " "))
+		return
+	case html.ElementNode:
+	default:
+		renderChildren(b, n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head:
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		writeBlock(b, strings.Repeat("#", level)+" "+inline(n))
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Details, atom.Summary:
+		b.WriteString("\n\n")
+		renderChildren(b, n)
+		b.WriteString("\n\n")
+	case atom.Br:
+		b.WriteString("\n")
+	case atom.Hr:
+		writeBlock(b, "---")
+	case atom.Strong, atom.B:
+		writeWrapped(b, "**", inline(n))
+	case atom.Em, atom.I:
+		writeWrapped(b, "_", inline(n))
+	case atom.Del, atom.S:
+		writeWrapped(b, "~~", inline(n))
+	case atom.Code:
+		writeWrapped(b, "`", textContent(n))
+	case atom.Pre:
+		writeBlock(b, "```\n"+strings.Trim(textContent(n), "\n")+"\n```")
+	case atom.A:
+		text := inline(n)
+		href := attr(n, "href")
+		if href == "" || href == text {
+			writeText(b, text)
+			return
+		}
+		writeText(b, fmt.Sprintf("[%s](%s)", text, href))
+	case atom.Img:
+		writeText(b, fmt.Sprintf("![%s](%s)", attr(n, "alt"), attr(n, "src")))
+	case atom.Ul, atom.Ol:
+		writeBlock(b, list(n))
+	case atom.Blockquote:
+		var inner strings.Builder
+		renderChildren(&inner, n)
+		lines := strings.Split(tidy(inner.String()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		writeBlock(b, strings.Join(lines, "\n"))
+	case atom.Table:
+		writeBlock(b, table(n))
+	default:
+		renderChildren(b, n)
+	}
+}
+
+// writeText appends inline text, dropping leading whitespace at the start of a line
+func writeText(b *strings.Builder, text string) {
+	current := b.String()
+	if current == "" || strings.HasSuffix(current, "\n") || strings.HasSuffix(current, " ") {
+		text = strings.TrimLeft(text, " ")
+	}
+	b.WriteString(text)
+}
+
+func writeWrapped(b *strings.Builder, marker, text string) {
+	if text == "" {
+		return
+	}
+	writeText(b, marker+text+marker)
+}
+
+func writeBlock(b *strings.Builder, block string) {
+	b.WriteString("\n\n")
+	b.WriteString(block)
+	b.WriteString("\n\n")
+}
+
+// inline renders the children of n onto a single line
+func inline(n *html.Node) string {
+	var b strings.Builder
+	renderChildren(&b, n)
+	return strings.TrimSpace(whitespaceRegexp.ReplaceAllString(b.String(), " "))
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func list(n *html.Node) string {
+	var lines []string
+	index := 1
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+
+		var inner strings.Builder
+		renderChildren(&inner, item)
+		content := nonEmptyLines(strings.Split(tidy(inner.String()), "\n"))
+
+		for i, line := range content {
+			if i == 0 {
+				lines = append(lines, marker+line)
+				continue
+			}
+			lines = append(lines, strings.Repeat(" ", len(marker))+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			switch child.DataAtom {
+			case atom.Tr:
+				var cells []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+						cells = append(cells, strings.ReplaceAll(inline(cell), "|", `\|`))
+					}
+				}
+				rows = append(rows, cells)
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(child)
+			}
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func nonEmptyLines(lines []string) []string {
+	result := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// tidy trims trailing spaces from each line and collapses runs of blank lines
+func tidy(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLinesRegexp.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
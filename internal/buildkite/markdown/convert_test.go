@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "paragraphs and emphasis",
+			input:    "<p>Build <strong>failed</strong> on <em>main</em></p><p>See   below</p>",
+			expected: "Build **failed** on _main_\n\nSee below",
+		},
+		{
+			name:     "headings and links",
+			input:    `<h3>Summary</h3><p>Read the <a href="https://buildkite.com/docs">docs</a></p>`,
+			expected: "### Summary\n\nRead the [docs](https://buildkite.com/docs)",
+		},
+		{
+			name:     "code blocks keep their formatting",
+			input:    "<p>Run <code>make test</code></p><pre><code>line one\n  indented two\n</code></pre>",
+			expected: "Run `make test`\n\n```\nline one\n  indented two\n```",
+		},
+		{
+			name:     "nested lists",
+			input:    "<ul><li>one</li><li>two<ol><li>a</li><li>b</li></ol></li></ul>",
+			expected: "- one\n- two\n  1. a\n  2. b",
+		},
+		{
+			name:     "tables",
+			input:    "<table><thead><tr><th>Test</th><th>Result</th></tr></thead><tbody><tr><td>a|b</td><td>failed</td></tr><tr><td>c</td></tr></tbody></table>",
+			expected: "| Test | Result |\n| --- | --- |\n| a\\|b | failed |\n| c |  |",
+		},
+		{
+			name:     "blockquotes",
+			input:    "<blockquote><p>first</p><p>second</p></blockquote>",
+			expected: "> first\n>\n> second",
+		},
+		{
+			name:     "scripts and styles are dropped",
+			input:    "<style>.x{}</style><div>visible</div><script>alert(1)</script>",
+			expected: "visible",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			output, err := FromHTML(tt.input)
+			assert.NoError(err)
+			assert.Equal(tt.expected, output)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -28,11 +29,11 @@ func UserTokenOrganization(ctx context.Context, client OrganizationsClient) (too
 
 			orgs, resp, err := client.List(ctx, &buildkite.OrganizationListOptions{})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list organizations", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("failed to get current user organizations"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list organizations", resp, nil), nil
 			}
 
 			if len(orgs) == 0 {
@@ -2,6 +2,7 @@ package buildkite
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -76,7 +77,12 @@ func TestUserTokenOrganizationError(t *testing.T) {
 
 	textContent := getTextResult(t, result)
 
-	assert.Equal("failed to get current user organizations", textContent.Text)
+	var apiErr APIError
+	assert.NoError(json.Unmarshal([]byte(textContent.Text), &apiErr))
+	assert.Equal("list organizations", apiErr.Operation)
+	assert.Equal(500, apiErr.Status)
+	assert.Equal(ErrorServer, apiErr.Category)
+	assert.NotEmpty(apiErr.Hint)
 }
 
 func TestUserTokenOrganizationErrorNoOrganization(t *testing.T) {
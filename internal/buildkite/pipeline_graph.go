@@ -0,0 +1,103 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/pipelineyaml"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PipelineGraph is the dependency graph of a pipeline's configured steps
+type PipelineGraph struct {
+	*pipelineyaml.Graph
+	Mermaid  string                 `json:"mermaid"`
+	Problems []pipelineyaml.Problem `json:"problems,omitempty"`
+}
+
+func GetPipelineGraph(ctx context.Context, client PipelinesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_pipeline_graph",
+			mcp.WithDescription("Get the dependency graph of a pipeline's configured steps, worked out from wait and block steps, depends_on keys and groups. Returns the nodes and edges, the stages of steps which can run at the same time (a stage with a single step is a point where the pipeline is serialised), and a Mermaid flowchart. Steps added at runtime by pipeline upload are not included"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get Pipeline Graph",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetPipelineGraph")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+			)
+
+			pipeline, resp, err := client.Get(ctx, org, pipelineSlug)
+			if err != nil {
+				return apiErrorResult("get pipeline", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get pipeline", resp, nil), nil
+			}
+
+			if strings.TrimSpace(pipeline.Configuration) == "" {
+				return mcp.NewToolResultError("pipeline has no YAML steps configuration"), nil
+			}
+
+			steps, problems := pipelineyaml.Parse([]byte(pipeline.Configuration))
+			if steps == nil {
+				messages := make([]string, len(problems))
+				for i, problem := range problems {
+					messages[i] = problem.String()
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("invalid pipeline yaml: %s", strings.Join(messages, "; "))), nil
+			}
+
+			graph, err := pipelineyaml.BuildGraph(steps)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.Int("nodes", len(graph.Nodes)),
+				attribute.Int("stages", len(graph.Stages)),
+			)
+
+			result := PipelineGraph{
+				Graph:    graph,
+				Mermaid:  graph.Mermaid(),
+				Problems: problems,
+			}
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pipeline graph: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
@@ -0,0 +1,47 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPipelineGraph(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockPipelinesClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
+			return buildkite.Pipeline{
+					Slug:          "pipeline",
+					Configuration: "steps:\n  - command: make test\n    key: test\n  - wait\n  - command: make deploy\n",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := GetPipelineGraph(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var graph PipelineGraph
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &graph))
+	assert.Len(graph.Nodes, 3)
+	assert.Equal([][]string{{"test"}, {"steps[2]"}}, graph.Stages)
+	assert.Equal(1, graph.MaxParallelism)
+	assert.Contains(graph.Mermaid, "n0 --> n1")
+}
@@ -0,0 +1,67 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/pipelineyaml"
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PipelineLintResult lists the problems found in a pipeline.yml, split by severity
+type PipelineLintResult struct {
+	Valid    bool                   `json:"valid"`
+	Errors   []pipelineyaml.Problem `json:"errors"`
+	Warnings []pipelineyaml.Problem `json:"warnings"`
+}
+
+func LintPipelineYAML(ctx context.Context) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("lint_pipeline_yaml",
+			mcp.WithDescription("Check a pipeline.yml without uploading it: reports YAML syntax errors, unknown step types, unknown keys and keys with the wrong type, depends_on entries which don't match a step key, duplicate keys and dependency cycles, each with its line and column"),
+			mcp.WithString("configuration",
+				mcp.Required(),
+				mcp.Description("The pipeline YAML to lint"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Lint Pipeline YAML",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			_, span := trace.Start(ctx, "buildkite.LintPipelineYAML")
+			defer span.End()
+
+			configuration, err := request.RequireString("configuration")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result := PipelineLintResult{
+				Errors:   make([]pipelineyaml.Problem, 0),
+				Warnings: make([]pipelineyaml.Problem, 0),
+			}
+			for _, problem := range pipelineyaml.Lint([]byte(configuration)) {
+				if problem.Severity == pipelineyaml.SeverityError {
+					result.Errors = append(result.Errors, problem)
+				} else {
+					result.Warnings = append(result.Warnings, problem)
+				}
+			}
+			result.Valid = len(result.Errors) == 0
+
+			span.SetAttributes(
+				attribute.Int("errors", len(result.Errors)),
+				attribute.Int("warnings", len(result.Warnings)),
+			)
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal lint result: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
@@ -0,0 +1,41 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintPipelineYAML(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	tool, handler := LintPipelineYAML(ctx)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"configuration": "steps:\n  - command: make\n    key: build\n    colour: blue\n  - wait\n  - command: make test\n    depends_on: bulid\n",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var lint PipelineLintResult
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &lint))
+	assert.False(lint.Valid)
+	assert.Len(lint.Errors, 1)
+	assert.Equal(`depends_on "bulid" does not match any step key`, lint.Errors[0].Message)
+	assert.Equal(7, lint.Errors[0].Line)
+	assert.Len(lint.Warnings, 1)
+	assert.Equal("steps[0].colour", lint.Warnings[0].Path)
+
+	request = createMCPRequest(t, map[string]any{
+		"configuration": "steps:\n  - command: make\n",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.JSONEq(`{"valid":true,"errors":[],"warnings":[]}`, getTextResult(t, result).Text)
+}
@@ -0,0 +1,376 @@
+package buildkite
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// defaultMetricsDays is the length of the window pipeline metrics are calculated over by default
+	defaultMetricsDays = 7
+	// maxMetricsBuilds caps how many builds are read to calculate pipeline metrics
+	maxMetricsBuilds = 1000
+	// maxMetricsBranches caps how many branches are broken out, busiest first
+	maxMetricsBranches = 20
+	// maxFailingSteps caps how many of the most frequently failing steps are listed
+	maxFailingSteps = 10
+)
+
+// DurationStats are the durations of finished builds, from starting to finishing
+type DurationStats struct {
+	MeanSeconds float64 `json:"mean_seconds"`
+	P50Seconds  float64 `json:"p50_seconds"`
+	P95Seconds  float64 `json:"p95_seconds"`
+}
+
+// TimeToGreen is how long a branch stayed broken, from the first failed build finishing to the next
+// passed build finishing
+type TimeToGreen struct {
+	Recoveries   int        `json:"recoveries"`
+	MeanSeconds  float64    `json:"mean_seconds"`
+	MaxSeconds   float64    `json:"max_seconds"`
+	FailingSince *time.Time `json:"failing_since,omitempty"`
+}
+
+// StepFailures counts the failed jobs of a step, including attempts which were retried
+type StepFailures struct {
+	Name     string `json:"name"`
+	StepKey  string `json:"step_key,omitempty"`
+	Failures int    `json:"failures"`
+	Builds   int    `json:"builds"`
+}
+
+// BranchMetrics summarise the builds of a branch, or of every branch when Branch is empty. The previous
+// and recent pass rates cover the first and second halves of the window.
+type BranchMetrics struct {
+	Branch                  string         `json:"branch,omitempty"`
+	Builds                  int            `json:"builds"`
+	Passed                  int            `json:"passed"`
+	Failed                  int            `json:"failed"`
+	Canceled                int            `json:"canceled"`
+	PassRatePercent         float64        `json:"pass_rate_percent"`
+	PreviousPassRatePercent *float64       `json:"previous_pass_rate_percent,omitempty"`
+	RecentPassRatePercent   *float64       `json:"recent_pass_rate_percent,omitempty"`
+	Duration                DurationStats  `json:"duration"`
+	TimeToGreen             TimeToGreen    `json:"time_to_green"`
+	FailingSteps            []StepFailures `json:"failing_steps"`
+}
+
+// PipelineMetrics are the health metrics of a pipeline's builds over a window
+type PipelineMetrics struct {
+	From            time.Time       `json:"from"`
+	To              time.Time       `json:"to"`
+	BuildsRead      int             `json:"builds_read"`
+	Truncated       bool            `json:"truncated,omitempty"`
+	Overall         BranchMetrics   `json:"overall"`
+	Branches        []BranchMetrics `json:"branches"`
+	OmittedBranches int             `json:"omitted_branches,omitempty"`
+}
+
+func GetPipelineMetrics(ctx context.Context, client BuildsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("pipeline_metrics",
+			mcp.WithDescription("Calculate health and trend metrics for a pipeline's builds over the last N days, overall and grouped by branch: pass rate (and how it changed between the first and second half of the window), mean/p50/p95 build duration, time-to-green after a failure, and the most frequently failing steps. Reads up to 1000 builds, most recent first"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("branch",
+				mcp.Description("Only include builds of this branch"),
+			),
+			mcp.WithNumber("days",
+				mcp.Description("Number of days to look back (default 7)"),
+				mcp.Min(1),
+				mcp.Max(90),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Pipeline Metrics",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.PipelineMetrics")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			branch := request.GetString("branch", "")
+
+			days := request.GetInt("days", defaultMetricsDays)
+			if days < 1 {
+				return mcp.NewToolResultError("days must be at least 1"), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("branch", branch),
+				attribute.Int("days", days),
+			)
+
+			to := time.Now().UTC()
+			from := to.AddDate(0, 0, -days)
+
+			options := &buildkite.BuildsListOptions{
+				CreatedFrom:        from,
+				IncludeRetriedJobs: true,
+				ExcludePipeline:    true,
+			}
+			if branch != "" {
+				options.Branch = []string{branch}
+			}
+
+			builds, truncated, err := listBuildsUpTo(ctx, client, org, pipelineSlug, options, maxMetricsBuilds)
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			result := pipelineMetrics(builds, from, to)
+			result.Truncated = truncated
+
+			span.SetAttributes(
+				attribute.Int("builds", result.BuildsRead),
+				attribute.Int("branches", len(result.Branches)+result.OmittedBranches),
+			)
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pipeline metrics: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func pipelineMetrics(builds []buildkite.Build, from, to time.Time) PipelineMetrics {
+	// the API lists builds newest first, but streaks are followed oldest first
+	builds = slices.Clone(builds)
+	slices.SortStableFunc(builds, func(a, b buildkite.Build) int {
+		return buildCreated(a).Compare(buildCreated(b))
+	})
+
+	result := PipelineMetrics{
+		From:       from,
+		To:         to,
+		BuildsRead: len(builds),
+		Branches:   make([]BranchMetrics, 0),
+	}
+
+	byBranch := make(map[string][]buildkite.Build)
+	var branches []string
+	for _, build := range builds {
+		if _, ok := byBranch[build.Branch]; !ok {
+			branches = append(branches, build.Branch)
+		}
+		byBranch[build.Branch] = append(byBranch[build.Branch], build)
+	}
+
+	slices.SortStableFunc(branches, func(a, b string) int {
+		return cmp.Compare(len(byBranch[b]), len(byBranch[a]))
+	})
+	if len(branches) > maxMetricsBranches {
+		result.OmittedBranches = len(branches) - maxMetricsBranches
+		branches = branches[:maxMetricsBranches]
+	}
+
+	midpoint := from.Add(to.Sub(from) / 2)
+
+	result.Overall = branchMetrics("", builds, midpoint)
+	for _, branch := range branches {
+		result.Branches = append(result.Branches, branchMetrics(branch, byBranch[branch], midpoint))
+	}
+
+	return result
+}
+
+// branchMetrics summarises builds ordered oldest first, which are all of one branch unless branch is
+// empty. Time to green is followed per branch, so it can be calculated across several branches at once.
+func branchMetrics(branch string, builds []buildkite.Build, midpoint time.Time) BranchMetrics {
+	metrics := BranchMetrics{
+		Branch:       branch,
+		Builds:       len(builds),
+		FailingSteps: make([]StepFailures, 0),
+	}
+
+	var (
+		durations                     []float64
+		recoveries                    []float64
+		previousPassed, previousTotal int
+		recentPassed, recentTotal     int
+		failingSince                  = make(map[string]time.Time)
+		steps                         = make(map[string]*StepFailures)
+		stepOrder                     []string
+	)
+
+	for _, build := range builds {
+		switch build.State {
+		case "passed", "failed":
+		case "canceled", "canceling":
+			metrics.Canceled++
+			continue
+		default:
+			continue
+		}
+
+		passed := build.State == "passed"
+		if passed {
+			metrics.Passed++
+		} else {
+			metrics.Failed++
+		}
+
+		if buildCreated(build).Before(midpoint) {
+			previousTotal++
+			if passed {
+				previousPassed++
+			}
+		} else {
+			recentTotal++
+			if passed {
+				recentPassed++
+			}
+		}
+
+		if build.StartedAt != nil && build.FinishedAt != nil {
+			durations = append(durations, build.FinishedAt.Sub(build.StartedAt.Time).Seconds())
+		}
+
+		if build.FinishedAt != nil {
+			since, failing := failingSince[build.Branch]
+			switch {
+			case !passed && !failing:
+				failingSince[build.Branch] = build.FinishedAt.Time
+			case passed && failing:
+				recoveries = append(recoveries, build.FinishedAt.Sub(since).Seconds())
+				delete(failingSince, build.Branch)
+			}
+		}
+
+		counted := make(map[string]bool)
+		for _, job := range build.Jobs {
+			if job.Type != "script" || job.SoftFailed || (job.State != "failed" && job.State != "timed_out") {
+				continue
+			}
+
+			id := job.StepKey
+			if id == "" {
+				id = job.Label
+			}
+			if id == "" {
+				id = job.Name
+			}
+
+			step, ok := steps[id]
+			if !ok {
+				step = &StepFailures{Name: job.Label, StepKey: job.StepKey}
+				if step.Name == "" {
+					step.Name = job.Name
+				}
+				steps[id] = step
+				stepOrder = append(stepOrder, id)
+			}
+			step.Failures++
+			if !counted[id] {
+				step.Builds++
+				counted[id] = true
+			}
+		}
+	}
+
+	metrics.PassRatePercent = percent(metrics.Passed, metrics.Passed+metrics.Failed)
+	if previousTotal > 0 {
+		rate := percent(previousPassed, previousTotal)
+		metrics.PreviousPassRatePercent = &rate
+	}
+	if recentTotal > 0 {
+		rate := percent(recentPassed, recentTotal)
+		metrics.RecentPassRatePercent = &rate
+	}
+
+	metrics.Duration = durationStats(durations)
+
+	metrics.TimeToGreen.Recoveries = len(recoveries)
+	if len(recoveries) > 0 {
+		metrics.TimeToGreen.MeanSeconds = round(mean(recoveries), 1)
+		metrics.TimeToGreen.MaxSeconds = round(slices.Max(recoveries), 1)
+	}
+	if since, ok := failingSince[branch]; ok && branch != "" {
+		metrics.TimeToGreen.FailingSince = &since
+	}
+
+	for _, id := range stepOrder {
+		metrics.FailingSteps = append(metrics.FailingSteps, *steps[id])
+	}
+	slices.SortStableFunc(metrics.FailingSteps, func(a, b StepFailures) int {
+		return cmp.Compare(b.Failures, a.Failures)
+	})
+	if len(metrics.FailingSteps) > maxFailingSteps {
+		metrics.FailingSteps = metrics.FailingSteps[:maxFailingSteps]
+	}
+
+	return metrics
+}
+
+func durationStats(durations []float64) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	return DurationStats{
+		MeanSeconds: round(mean(sorted), 1),
+		P50Seconds:  round(percentile(sorted, 50), 1),
+		P95Seconds:  round(percentile(sorted, 95), 1),
+	}
+}
+
+// percentile uses the nearest rank of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round(float64(n)*100/float64(total), 1)
+}
+
+func buildCreated(build buildkite.Build) time.Time {
+	if build.CreatedAt == nil {
+		return time.Time{}
+	}
+	return build.CreatedAt.Time
+}
@@ -0,0 +1,104 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPipelineMetrics(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	build := func(number int, branch, state string, daysAgo, seconds int, jobs ...buildkite.Job) buildkite.Build {
+		created := now.AddDate(0, 0, -daysAgo)
+		return buildkite.Build{
+			Number:     number,
+			Branch:     branch,
+			State:      state,
+			CreatedAt:  &buildkite.Timestamp{Time: created},
+			StartedAt:  &buildkite.Timestamp{Time: created},
+			FinishedAt: &buildkite.Timestamp{Time: created.Add(time.Duration(seconds) * time.Second)},
+			Jobs:       jobs,
+		}
+	}
+	failedTest := buildkite.Job{Type: "script", StepKey: "test", Label: "Test", State: "failed"}
+
+	pages := [][]buildkite.Build{
+		{
+			build(7, "feature", "canceled", 1, 60),
+			build(6, "feature", "passed", 1, 120),
+			build(5, "main", "failed", 1, 300, failedTest, buildkite.Job{Type: "script", Label: "Lint", State: "failed", SoftFailed: true}),
+			build(4, "main", "passed", 2, 900),
+		},
+		{
+			build(3, "main", "failed", 4, 300, failedTest),
+			build(2, "main", "failed", 5, 300, failedTest, buildkite.Job{Type: "script", StepKey: "test", Label: "Test", State: "failed", Retried: true}),
+			build(1, "main", "passed", 6, 600),
+		},
+	}
+
+	client := &MockBuildsClient{
+		ListByPipelineFunc: func(ctx context.Context, org string, pipeline string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error) {
+			page, nextPage := 0, 2
+			if opt.Page == 2 {
+				page, nextPage = 1, 0
+			}
+			return pages[page], &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: nextPage,
+				}, nil
+		},
+	}
+
+	tool, handler := GetPipelineMetrics(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var metrics PipelineMetrics
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &metrics))
+	assert.Equal(7, metrics.BuildsRead)
+	assert.False(metrics.Truncated)
+
+	overall := metrics.Overall
+	assert.Equal(3, overall.Passed)
+	assert.Equal(3, overall.Failed)
+	assert.Equal(1, overall.Canceled)
+	assert.Equal(50.0, overall.PassRatePercent)
+	assert.Equal(33.3, *overall.PreviousPassRatePercent)
+	assert.Equal(66.7, *overall.RecentPassRatePercent)
+	assert.Equal(DurationStats{MeanSeconds: 420, P50Seconds: 300, P95Seconds: 900}, overall.Duration)
+	assert.Equal([]StepFailures{{Name: "Test", StepKey: "test", Failures: 4, Builds: 3}}, overall.FailingSteps)
+	assert.Nil(overall.TimeToGreen.FailingSince)
+
+	assert.Len(metrics.Branches, 2)
+	mainBranch := metrics.Branches[0]
+	assert.Equal("main", mainBranch.Branch)
+	assert.Equal(5, mainBranch.Builds)
+	assert.Equal(40.0, mainBranch.PassRatePercent)
+	assert.Equal(50.0, *mainBranch.RecentPassRatePercent)
+	assert.Equal(1, mainBranch.TimeToGreen.Recoveries)
+	assert.Equal((3*24*time.Hour + 600*time.Second).Seconds(), mainBranch.TimeToGreen.MeanSeconds)
+	assert.NotNil(mainBranch.TimeToGreen.FailingSince)
+
+	feature := metrics.Branches[1]
+	assert.Equal("feature", feature.Branch)
+	assert.Equal(100.0, feature.PassRatePercent)
+	assert.Empty(feature.FailingSteps)
+}
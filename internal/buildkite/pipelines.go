@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite/pipelineyaml"
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -17,6 +18,8 @@ import (
 type PipelinesClient interface {
 	Get(ctx context.Context, org, pipelineSlug string) (buildkite.Pipeline, *buildkite.Response, error)
 	List(ctx context.Context, org string, options *buildkite.PipelineListOptions) ([]buildkite.Pipeline, *buildkite.Response, error)
+	Create(ctx context.Context, org string, p buildkite.CreatePipeline) (buildkite.Pipeline, *buildkite.Response, error)
+	Update(ctx context.Context, org, pipelineSlug string, p buildkite.UpdatePipeline) (buildkite.Pipeline, *buildkite.Response, error)
 }
 
 func ListPipelines(ctx context.Context, client PipelinesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
@@ -55,15 +58,11 @@ func ListPipelines(ctx context.Context, client PipelinesClient) (tool mcp.Tool,
 				ListOptions: paginationParams,
 			})
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list pipelines", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
+				return apiErrorResult("list pipelines", resp, nil), nil
 			}
 
 			result := PaginatedResult[buildkite.Pipeline]{
@@ -119,15 +118,11 @@ func GetPipeline(ctx context.Context, client PipelinesClient) (tool mcp.Tool, ha
 
 			pipeline, resp, err := client.Get(ctx, org, pipelineSlug)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get pipeline", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
+				return apiErrorResult("get pipeline", resp, nil), nil
 			}
 
 			r, err := json.Marshal(&pipeline)
@@ -138,3 +133,237 @@ func GetPipeline(ctx context.Context, client PipelinesClient) (tool mcp.Tool, ha
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+func CreatePipeline(ctx context.Context, client PipelinesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_pipeline",
+			mcp.WithDescription("Create a new pipeline in an organization. The steps YAML is validated locally before the pipeline is created"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the pipeline"),
+			),
+			mcp.WithString("repository",
+				mcp.Required(),
+				mcp.Description("The git repository URL of the pipeline"),
+			),
+			mcp.WithString("configuration",
+				mcp.Required(),
+				mcp.Description("The pipeline steps as YAML, e.g. the contents of a pipeline.yml"),
+			),
+			mcp.WithString("default_branch",
+				mcp.Description("The default branch for builds and metrics (default main)"),
+			),
+			mcp.WithString("description",
+				mcp.Description("A description of the pipeline"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Description("The ID of the cluster the pipeline runs in"),
+			),
+			mcp.WithArray("tags",
+				mcp.Description("Tags to add to the pipeline"),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Create Pipeline",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(false),
+				IdempotentHint:  mcp.ToBoolPtr(false),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.CreatePipeline")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			name, err := request.RequireString("name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			repository, err := request.RequireString("repository")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			configuration, err := request.RequireString("configuration")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			if err := validatePipelineConfiguration(configuration); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			create := buildkite.CreatePipeline{
+				Name:          name,
+				Repository:    repository,
+				Configuration: configuration,
+				DefaultBranch: request.GetString("default_branch", ""),
+				Description:   request.GetString("description", ""),
+				ClusterID:     request.GetString("cluster_id", ""),
+				Tags:          request.GetStringSlice("tags", nil),
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("name", name),
+				attribute.String("repository", repository),
+				attribute.String("cluster_id", create.ClusterID),
+			)
+
+			pipeline, resp, err := client.Create(ctx, org, create)
+			if err != nil {
+				return apiErrorResult("create pipeline", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusCreated {
+				return apiErrorResult("create pipeline", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&pipeline)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pipeline: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+func UpdatePipeline(ctx context.Context, client PipelinesClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_pipeline",
+			mcp.WithDescription("Update the settings or steps of an existing pipeline. Only the fields provided are changed, and new steps YAML is validated locally before the pipeline is updated"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the pipeline"),
+			),
+			mcp.WithString("name",
+				mcp.Description("The new name of the pipeline"),
+			),
+			mcp.WithString("repository",
+				mcp.Description("The new git repository URL of the pipeline"),
+			),
+			mcp.WithString("configuration",
+				mcp.Description("The new pipeline steps as YAML, replacing the existing steps"),
+			),
+			mcp.WithString("default_branch",
+				mcp.Description("The new default branch"),
+			),
+			mcp.WithString("description",
+				mcp.Description("The new description"),
+			),
+			mcp.WithString("cluster_id",
+				mcp.Description("The ID of the cluster to move the pipeline to"),
+			),
+			mcp.WithArray("tags",
+				mcp.Description("Tags for the pipeline, replacing the existing tags"),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           "Update Pipeline",
+				ReadOnlyHint:    mcp.ToBoolPtr(false),
+				DestructiveHint: mcp.ToBoolPtr(true),
+				IdempotentHint:  mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.UpdatePipeline")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			pipelineSlug, err := request.RequireString("pipeline_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			update := buildkite.UpdatePipeline{
+				Name:          request.GetString("name", ""),
+				Repository:    request.GetString("repository", ""),
+				Configuration: request.GetString("configuration", ""),
+				DefaultBranch: request.GetString("default_branch", ""),
+				Description:   request.GetString("description", ""),
+				ClusterID:     request.GetString("cluster_id", ""),
+				Tags:          request.GetStringSlice("tags", nil),
+			}
+
+			if update.Name == "" && update.Repository == "" && update.Configuration == "" && update.DefaultBranch == "" &&
+				update.Description == "" && update.ClusterID == "" && update.Tags == nil {
+				return mcp.NewToolResultError("nothing to update, provide at least one field to change"), nil
+			}
+
+			if update.Configuration != "" {
+				if err := validatePipelineConfiguration(update.Configuration); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.Bool("configuration", update.Configuration != ""),
+			)
+
+			// The update always sends the branch build settings, so carry over the current values
+			// rather than silently switching them off
+			current, resp, err := client.Get(ctx, org, pipelineSlug)
+			if err != nil {
+				return apiErrorResult("get pipeline", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get pipeline", resp, nil), nil
+			}
+
+			update.SkipQueuedBranchBuilds = current.SkipQueuedBranchBuilds
+			update.SkipQueuedBranchBuildsFilter = current.SkipQueuedBranchBuildsFilter
+			update.CancelRunningBranchBuilds = current.CancelRunningBranchBuilds
+			update.CancelRunningBranchBuildsFilter = current.CancelRunningBranchBuildsFilter
+
+			pipeline, resp, err := client.Update(ctx, org, pipelineSlug, update)
+			if err != nil {
+				return apiErrorResult("update pipeline", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("update pipeline", resp, nil), nil
+			}
+
+			r, err := json.Marshal(&pipeline)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pipeline: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// validatePipelineConfiguration lints the YAML and fails on any errors, as the API accepts a broken
+// configuration and the mistake only shows up when the next build fails to upload its steps
+func validatePipelineConfiguration(configuration string) error {
+	problems := pipelineyaml.Lint([]byte(configuration))
+	if !pipelineyaml.HasErrors(problems) {
+		return nil
+	}
+
+	var messages []string
+	for _, problem := range problems {
+		if problem.Severity == pipelineyaml.SeverityError {
+			messages = append(messages, problem.String())
+		}
+	}
+	return fmt.Errorf("invalid pipeline yaml: %s", strings.Join(messages, "; "))
+}
@@ -10,8 +10,10 @@ import (
 )
 
 type MockPipelinesClient struct {
-	GetFunc  func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error)
-	ListFunc func(ctx context.Context, org string, opt *buildkite.PipelineListOptions) ([]buildkite.Pipeline, *buildkite.Response, error)
+	GetFunc    func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error)
+	ListFunc   func(ctx context.Context, org string, opt *buildkite.PipelineListOptions) ([]buildkite.Pipeline, *buildkite.Response, error)
+	CreateFunc func(ctx context.Context, org string, p buildkite.CreatePipeline) (buildkite.Pipeline, *buildkite.Response, error)
+	UpdateFunc func(ctx context.Context, org, pipelineSlug string, p buildkite.UpdatePipeline) (buildkite.Pipeline, *buildkite.Response, error)
 }
 
 func (m *MockPipelinesClient) Get(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
@@ -28,6 +30,20 @@ func (m *MockPipelinesClient) List(ctx context.Context, org string, opt *buildki
 	return nil, nil, nil
 }
 
+func (m *MockPipelinesClient) Create(ctx context.Context, org string, p buildkite.CreatePipeline) (buildkite.Pipeline, *buildkite.Response, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org, p)
+	}
+	return buildkite.Pipeline{}, nil, nil
+}
+
+func (m *MockPipelinesClient) Update(ctx context.Context, org, pipelineSlug string, p buildkite.UpdatePipeline) (buildkite.Pipeline, *buildkite.Response, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, org, pipelineSlug, p)
+	}
+	return buildkite.Pipeline{}, nil, nil
+}
+
 var _ PipelinesClient = (*MockPipelinesClient)(nil)
 
 func TestListPipelines(t *testing.T) {
@@ -100,3 +116,136 @@ func TestGetPipeline(t *testing.T) {
 
 	assert.Equal(`{"id":"123","name":"Test Pipeline","slug":"test-pipeline","created_at":"0001-01-01T00:00:00Z","skip_queued_branch_builds":false,"cancel_running_branch_builds":false,"provider":{"id":"","webhook_url":"","settings":null}}`, textContent.Text)
 }
+
+func TestCreatePipeline(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockPipelinesClient{
+		CreateFunc: func(ctx context.Context, org string, p buildkite.CreatePipeline) (buildkite.Pipeline, *buildkite.Response, error) {
+			assert.Equal("org", org)
+			assert.Equal("My Pipeline", p.Name)
+			assert.Equal("git@github.com:org/repo.git", p.Repository)
+			assert.Equal("cluster-id", p.ClusterID)
+			assert.Equal([]string{"team:web", "tier:1"}, p.Tags)
+			return buildkite.Pipeline{
+					Slug: "my-pipeline",
+					Name: p.Name,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 201,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := CreatePipeline(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"name":          "My Pipeline",
+		"repository":    "git@github.com:org/repo.git",
+		"configuration": "steps:\n  - command: make test\n  - wait\n  - label: deploy\n    trigger: deploy\n",
+		"cluster_id":    "cluster-id",
+		"tags":          []any{"team:web", "tier:1"},
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.False(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, `"slug":"my-pipeline"`)
+}
+
+func TestCreatePipeline_InvalidConfiguration(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockPipelinesClient{
+		CreateFunc: func(ctx context.Context, org string, p buildkite.CreatePipeline) (buildkite.Pipeline, *buildkite.Response, error) {
+			assert.Fail("pipeline should not be created with invalid configuration")
+			return buildkite.Pipeline{}, nil, nil
+		},
+	}
+
+	_, handler := CreatePipeline(ctx, client)
+
+	tests := []struct {
+		configuration string
+		expected      string
+	}{
+		{"steps: [", "invalid pipeline yaml: 1: did not find expected node content"},
+		{"env:\n  FOO: bar\n", "invalid pipeline yaml: 1:1: missing steps"},
+		{"steps:\n  command: make\n", "2:3: steps must be a list"},
+		{"steps:\n  - label: nothing to do\n", "2:5: step has no type"},
+		{"steps:\n  - waitt\n", `2:5: unknown step "waitt"`},
+		{"steps:\n  - command: make\n    depends_on: missing\n", `3:17: depends_on "missing" does not match any step key`},
+	}
+
+	for _, tt := range tests {
+		result, err := handler(ctx, createMCPRequest(t, map[string]any{
+			"org":           "org",
+			"name":          "My Pipeline",
+			"repository":    "git@github.com:org/repo.git",
+			"configuration": tt.configuration,
+		}))
+		assert.NoError(err)
+		assert.True(result.IsError)
+		assert.Contains(getTextResult(t, result).Text, tt.expected)
+	}
+}
+
+func TestUpdatePipeline(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockPipelinesClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
+			return buildkite.Pipeline{
+					Slug:                         "my-pipeline",
+					SkipQueuedBranchBuilds:       true,
+					SkipQueuedBranchBuildsFilter: "!main",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		UpdateFunc: func(ctx context.Context, org, pipelineSlug string, p buildkite.UpdatePipeline) (buildkite.Pipeline, *buildkite.Response, error) {
+			assert.Equal("my-pipeline", pipelineSlug)
+			assert.Equal("develop", p.DefaultBranch)
+			assert.Empty(p.Name)
+			assert.True(p.SkipQueuedBranchBuilds)
+			assert.Equal("!main", p.SkipQueuedBranchBuildsFilter)
+			return buildkite.Pipeline{
+					Slug:          pipelineSlug,
+					DefaultBranch: p.DefaultBranch,
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := UpdatePipeline(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	result, err := handler(ctx, createMCPRequest(t, map[string]any{
+		"org":            "org",
+		"pipeline_slug":  "my-pipeline",
+		"default_branch": "develop",
+	}))
+	assert.NoError(err)
+	assert.False(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, `"default_branch":"develop"`)
+
+	// at least one field is required
+	result, err = handler(ctx, createMCPRequest(t, map[string]any{
+		"org":           "org",
+		"pipeline_slug": "my-pipeline",
+	}))
+	assert.NoError(err)
+	assert.True(result.IsError)
+}
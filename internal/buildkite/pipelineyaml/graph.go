@@ -0,0 +1,346 @@
+package pipelineyaml
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Edge types
+const (
+	// EdgeImplicit is an ordering created by a wait or block step
+	EdgeImplicit = "implicit"
+	// EdgeDependsOn is an ordering created by a depends_on key
+	EdgeDependsOn = "depends_on"
+)
+
+// GraphNode is a step in the dependency graph
+type GraphNode struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+	Group string `json:"group,omitempty"`
+	Line  int    `json:"line"`
+	Stage int    `json:"stage,omitempty"`
+}
+
+// GraphEdge means the To step waits for the From step to finish
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// Graph is the dependency graph of a pipeline's steps. Stages lists the steps which can run at the
+// same time, in the order they become runnable, so a stage with a single step is a point where the
+// pipeline is serialised. Wait steps and groups order other steps but don't make up a stage.
+type Graph struct {
+	Nodes          []GraphNode `json:"nodes"`
+	Edges          []GraphEdge `json:"edges"`
+	Stages         [][]string  `json:"stages"`
+	MaxParallelism int         `json:"max_parallelism"`
+}
+
+type graphBuilder struct {
+	graph    *Graph
+	ids      map[*Step]string
+	steps    map[string]*Step
+	keyed    map[string]*Step
+	deps     map[*Step][]*Step
+	groups   map[*Step]*Step
+	finished map[*Step]int
+	levels   map[*Step]int
+	visiting map[*Step]bool
+}
+
+// BuildGraph works out the order steps run in from wait and block steps, which each wait for every
+// step before them, and depends_on keys. Steps in a group also wait for whatever the group waits for.
+func BuildGraph(pipeline *Pipeline) (*Graph, error) {
+	b := &graphBuilder{
+		graph: &Graph{
+			Nodes:  make([]GraphNode, 0),
+			Edges:  make([]GraphEdge, 0),
+			Stages: make([][]string, 0),
+		},
+		ids:      make(map[*Step]string),
+		steps:    make(map[string]*Step),
+		keyed:    make(map[string]*Step),
+		deps:     make(map[*Step][]*Step),
+		groups:   make(map[*Step]*Step),
+		finished: make(map[*Step]int),
+		levels:   make(map[*Step]int),
+		visiting: make(map[*Step]bool),
+	}
+
+	used := make(map[string]bool)
+	pipeline.Walk(func(step *Step, group *Step) {
+		id := step.Key
+		if id == "" || used[id] {
+			id = step.Path
+		}
+		used[id] = true
+		b.ids[step] = id
+		b.steps[id] = step
+
+		if step.Key != "" {
+			if _, ok := b.keyed[step.Key]; !ok {
+				b.keyed[step.Key] = step
+			}
+		}
+		if group != nil {
+			b.groups[step] = group
+		}
+	})
+
+	b.sequence(pipeline.Steps)
+
+	pipeline.Walk(func(step *Step, group *Step) {
+		for _, key := range step.DependsOn {
+			if dep, ok := b.keyed[key]; ok {
+				b.deps[step] = append(b.deps[step], dep)
+				b.graph.Edges = append(b.graph.Edges, GraphEdge{From: b.ids[dep], To: b.ids[step], Type: EdgeDependsOn})
+			}
+		}
+	})
+
+	var err error
+	pipeline.Walk(func(step *Step, group *Step) {
+		if err == nil {
+			_, err = b.finish(step)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range b.graph.Nodes {
+		node := &b.graph.Nodes[i]
+		if node.Type == TypeWait || node.Type == TypeGroup {
+			continue
+		}
+		node.Stage = b.levels[b.steps[node.ID]]
+		for len(b.graph.Stages) < node.Stage {
+			b.graph.Stages = append(b.graph.Stages, nil)
+		}
+		b.graph.Stages[node.Stage-1] = append(b.graph.Stages[node.Stage-1], node.ID)
+	}
+
+	for _, stage := range b.graph.Stages {
+		b.graph.MaxParallelism = max(b.graph.MaxParallelism, len(stage))
+	}
+
+	return b.graph, nil
+}
+
+// sequence adds the steps of the pipeline or a group in order, with the implicit dependencies wait and
+// block steps create between them
+func (b *graphBuilder) sequence(steps []*Step) {
+	walkSequence(steps, func(step *Step, deps []*Step) {
+		node := GraphNode{
+			ID:    b.ids[step],
+			Type:  step.Type,
+			Label: step.Label,
+			Line:  step.Line,
+		}
+		if group, ok := b.groups[step]; ok {
+			node.Group = b.ids[group]
+		}
+		b.graph.Nodes = append(b.graph.Nodes, node)
+
+		for _, dep := range deps {
+			b.graph.Edges = append(b.graph.Edges, GraphEdge{From: b.ids[dep], To: b.ids[step], Type: EdgeImplicit})
+		}
+		b.deps[step] = append(b.deps[step], deps...)
+	})
+}
+
+// walkSequence calls fn for each step in order, and the steps of groups after their group, with the
+// steps it implicitly waits for: a wait or block step waits for every step since the last one, and
+// other steps wait for the last one
+func walkSequence(steps []*Step, fn func(step *Step, deps []*Step)) {
+	var current, since []*Step
+	for _, step := range steps {
+		deps := current
+		switch step.Type {
+		case TypeWait, TypeBlock:
+			if len(since) > 0 {
+				deps = since
+			}
+			current, since = []*Step{step}, nil
+		default:
+			since = append(since, step)
+		}
+
+		fn(step, deps)
+
+		if step.Type == TypeGroup {
+			walkSequence(step.Steps, fn)
+		}
+	}
+}
+
+// level is the stage a step runs in, which is one after the latest stage any of its dependencies
+// finish in. Wait steps and groups take no time of their own.
+func (b *graphBuilder) level(step *Step) (int, error) {
+	if level, ok := b.levels[step]; ok {
+		return level, nil
+	}
+	if b.visiting[step] {
+		return 0, fmt.Errorf("dependency cycle involving %s", b.ids[step])
+	}
+	b.visiting[step] = true
+	defer delete(b.visiting, step)
+
+	deps := b.deps[step]
+	if group, ok := b.groups[step]; ok {
+		deps = append(deps[:len(deps):len(deps)], b.deps[group]...)
+	}
+
+	start := 0
+	for _, dep := range deps {
+		finished, err := b.finish(dep)
+		if err != nil {
+			return 0, err
+		}
+		start = max(start, finished)
+	}
+
+	level := start
+	if step.Type != TypeWait && step.Type != TypeGroup {
+		level++
+	}
+	b.levels[step] = level
+	return level, nil
+}
+
+// finish is the stage a step finishes in, which for a group is when the last of its steps finishes
+func (b *graphBuilder) finish(step *Step) (int, error) {
+	if finished, ok := b.finished[step]; ok {
+		return finished, nil
+	}
+
+	finished, err := b.level(step)
+	if err != nil {
+		return 0, err
+	}
+	for _, child := range step.Steps {
+		childFinished, err := b.finish(child)
+		if err != nil {
+			return 0, err
+		}
+		finished = max(finished, childFinished)
+	}
+
+	b.finished[step] = finished
+	return finished, nil
+}
+
+// Dependencies returns the steps which must finish before a step can run, looking through wait steps
+// and groups to the steps they wait for. A step in a group also waits for whatever the group waits for.
+func (g *Graph) Dependencies(id string) []string {
+	nodes := make(map[string]GraphNode, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+
+	var (
+		deps       []string
+		seen       = make(map[string]bool)
+		add        func(id string)
+		addWaitFor func(id string)
+	)
+	addWaitFor = func(id string) {
+		group := nodes[id].Group
+		for _, edge := range g.Edges {
+			if edge.To == id || (group != "" && edge.To == group) {
+				add(edge.From)
+			}
+		}
+	}
+	add = func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		switch nodes[id].Type {
+		case TypeWait:
+			addWaitFor(id)
+		case TypeGroup:
+			for _, node := range g.Nodes {
+				if node.Group == id {
+					add(node.ID)
+				}
+			}
+		default:
+			deps = append(deps, id)
+		}
+	}
+
+	addWaitFor(id)
+	return deps
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, with groups as subgraphs and depends_on
+// edges dashed
+func (g *Graph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+
+	for _, node := range g.Nodes {
+		if node.Group != "" {
+			continue
+		}
+		if node.Type != TypeGroup {
+			fmt.Fprintf(&sb, "  %s\n", mermaidNode(ids[node.ID], node))
+			continue
+		}
+
+		fmt.Fprintf(&sb, "  subgraph %s[\"%s\"]\n", ids[node.ID], mermaidLabel(node))
+		for _, child := range g.Nodes {
+			if child.Group == node.ID {
+				fmt.Fprintf(&sb, "    %s\n", mermaidNode(ids[child.ID], child))
+			}
+		}
+		sb.WriteString("  end\n")
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Type == EdgeDependsOn {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+
+	return sb.String()
+}
+
+func mermaidNode(id string, node GraphNode) string {
+	label := mermaidLabel(node)
+	switch node.Type {
+	case TypeWait:
+		return fmt.Sprintf("%s{{\"%s\"}}", id, label)
+	case TypeBlock, TypeInput:
+		return fmt.Sprintf("%s[/\"%s\"/]", id, label)
+	case TypeTrigger:
+		return fmt.Sprintf("%s[[\"%s\"]]", id, label)
+	default:
+		return fmt.Sprintf("%s[\"%s\"]", id, label)
+	}
+}
+
+func mermaidLabel(node GraphNode) string {
+	label := node.Label
+	if label == "" {
+		label = node.ID
+		if node.Type == TypeWait {
+			label = "wait"
+		}
+	}
+	return strings.NewReplacer("\"", "#quot;", "\n", " ").Replace(label)
+}
@@ -0,0 +1,143 @@
+package pipelineyaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGraph(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline, problems := Parse([]byte(`steps:
+  - command: make lint
+    key: lint
+  - command: make test
+    key: test
+  - wait
+  - command: make package
+    key: package
+  - group: Deploy
+    key: deploy
+    depends_on: package
+    steps:
+      - command: make deploy-us
+        key: us
+      - command: make deploy-eu
+        key: eu
+        depends_on: us
+  - label: "say \"done\""
+    command: echo done
+    depends_on: deploy
+`))
+	assert.Empty(problems)
+
+	graph, err := BuildGraph(pipeline)
+	assert.NoError(err)
+
+	assert.Equal([][]string{{"lint", "test"}, {"package"}, {"us"}, {"eu"}, {"steps[5]"}}, graph.Stages)
+	assert.Equal(2, graph.MaxParallelism)
+	assert.Equal([]GraphEdge{
+		{From: "lint", To: "steps[2]", Type: EdgeImplicit},
+		{From: "test", To: "steps[2]", Type: EdgeImplicit},
+		{From: "steps[2]", To: "package", Type: EdgeImplicit},
+		{From: "steps[2]", To: "deploy", Type: EdgeImplicit},
+		{From: "steps[2]", To: "steps[5]", Type: EdgeImplicit},
+		{From: "package", To: "deploy", Type: EdgeDependsOn},
+		{From: "us", To: "eu", Type: EdgeDependsOn},
+		{From: "deploy", To: "steps[5]", Type: EdgeDependsOn},
+	}, graph.Edges)
+	assert.Equal("deploy", graph.Nodes[5].Group)
+
+	assert.Equal(`flowchart TD
+  n0["lint"]
+  n1["test"]
+  n2{{"wait"}}
+  n3["package"]
+  subgraph n4["Deploy"]
+    n5["us"]
+    n6["eu"]
+  end
+  n7["say #quot;done#quot;"]
+  n0 --> n2
+  n1 --> n2
+  n2 --> n3
+  n2 --> n4
+  n2 --> n7
+  n3 -.-> n4
+  n5 -.-> n6
+  n4 -.-> n7
+`, graph.Mermaid())
+}
+
+func TestGraphDependencies(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline, problems := Parse([]byte(`steps:
+  - command: make lint
+    key: lint
+  - command: make test
+    key: test
+  - wait
+  - command: make package
+    key: package
+  - group: Deploy
+    key: deploy
+    depends_on: package
+    steps:
+      - command: make deploy-us
+        key: us
+      - command: make deploy-eu
+        key: eu
+        depends_on: us
+  - command: echo done
+    depends_on: deploy
+`))
+	assert.Empty(problems)
+
+	graph, err := BuildGraph(pipeline)
+	assert.NoError(err)
+
+	assert.Empty(graph.Dependencies("lint"))
+	assert.Equal([]string{"lint", "test"}, graph.Dependencies("package"))
+	assert.Equal([]string{"lint", "test", "package"}, graph.Dependencies("us"))
+	assert.Equal([]string{"lint", "test", "package", "us"}, graph.Dependencies("eu"))
+	assert.Equal([]string{"lint", "test", "us", "eu"}, graph.Dependencies("steps[5]"))
+}
+
+func TestBuildGraphBlockSteps(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline, problems := Parse([]byte(`steps:
+  - command: build
+    key: build
+  - block: Release?
+    key: release
+  - input: Notes
+    key: notes
+  - command: release
+    key: ship
+`))
+	assert.Empty(problems)
+
+	graph, err := BuildGraph(pipeline)
+	assert.NoError(err)
+	assert.Equal([][]string{{"build"}, {"release"}, {"notes", "ship"}}, graph.Stages)
+}
+
+func TestBuildGraphCycle(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline, problems := Parse([]byte(`steps:
+  - command: a
+    key: a
+    depends_on: b
+  - wait
+  - command: b
+    key: b
+`))
+	assert.Empty(problems)
+
+	_, err := BuildGraph(pipeline)
+	assert.ErrorContains(err, "dependency cycle")
+}
@@ -0,0 +1,306 @@
+package pipelineyaml
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// schema lists the keys allowed at the top level of a pipeline, on every step, and on each type of
+// step, along with their types. Types are separated by | where more than one is accepted.
+type schema struct {
+	Pipeline map[string]string            `json:"pipeline"`
+	Common   map[string]string            `json:"common"`
+	Steps    map[string]map[string]string `json:"steps"`
+}
+
+var stepSchema = mustLoadSchema()
+
+func mustLoadSchema() schema {
+	var s schema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		panic(fmt.Sprintf("invalid embedded pipeline schema: %s", err))
+	}
+	return s
+}
+
+// Lint parses a pipeline.yml and checks its keys and their types, that each depends_on refers to a
+// step key which exists, and that the dependencies don't form a cycle. Problems are ordered by position.
+func Lint(data []byte) []Problem {
+	pipeline, parsed := Parse(data)
+	probs := problems(parsed)
+
+	if pipeline != nil {
+		if pipeline.root.Kind == yaml.MappingNode {
+			checkKeys(&probs, pipeline.root, "", "pipeline", stepSchema.Pipeline)
+		}
+
+		pipeline.Walk(func(step *Step, group *Step) {
+			if step.node.Kind != yaml.MappingNode {
+				return
+			}
+			// conflicting type keys have already been reported while parsing
+			allowed := make(map[string]string, len(typeKeys)+len(stepSchema.Common)+len(stepSchema.Steps[step.Type]))
+			for key := range typeKeys {
+				allowed[key] = "any"
+			}
+			for key, types := range stepSchema.Common {
+				allowed[key] = types
+			}
+			for key, types := range stepSchema.Steps[step.Type] {
+				allowed[key] = types
+			}
+			checkKeys(&probs, step.node, step.Path, step.Type+" step", allowed)
+		})
+
+		checkDependencies(&probs, pipeline)
+	}
+
+	sort.SliceStable(probs, func(i, j int) bool {
+		if probs[i].Line != probs[j].Line {
+			return probs[i].Line < probs[j].Line
+		}
+		return probs[i].Column < probs[j].Column
+	})
+
+	return probs
+}
+
+// HasErrors reports whether any of the problems are errors rather than warnings
+func HasErrors(probs []Problem) bool {
+	for _, p := range probs {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func checkKeys(probs *problems, node *yaml.Node, path, what string, allowed map[string]string) {
+	for _, pair := range pairs(node) {
+		key := pair.key.Value
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		types, ok := allowed[key]
+		if !ok {
+			probs.warnf(pair.key, keyPath, "unknown key %q for a %s", key, what)
+			continue
+		}
+
+		if !matchesType(pair.value, types) {
+			expected := strings.ReplaceAll(types, "|", " or ")
+			article := "a"
+			if strings.ContainsRune("aeiou", rune(expected[0])) {
+				article = "an"
+			}
+			probs.errorf(pair.value, keyPath, "%s must be %s %s", key, article, expected)
+			continue
+		}
+
+		if types == "depends_on" {
+			checkDependsOn(probs, pair.value, keyPath)
+		}
+	}
+}
+
+func matchesType(node *yaml.Node, types string) bool {
+	for _, t := range strings.Split(types, "|") {
+		if matches(node, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(node *yaml.Node, t string) bool {
+	scalar := node.Kind == yaml.ScalarNode
+	null := scalar && node.Tag == "!!null"
+
+	// values can be interpolated from environment variables when the pipeline is uploaded
+	interpolated := scalar && node.Tag == "!!str" && strings.Contains(node.Value, "$")
+
+	switch t {
+	case "any":
+		return true
+	case "string":
+		return scalar && !null
+	case "integer":
+		return scalar && (node.Tag == "!!int" || interpolated)
+	case "boolean":
+		return scalar && (node.Tag == "!!bool" || interpolated)
+	case "null":
+		return null
+	case "map":
+		return node.Kind == yaml.MappingNode
+	case "list", "steps":
+		return node.Kind == yaml.SequenceNode
+	case "depends_on":
+		return scalar || node.Kind == yaml.SequenceNode
+	}
+	return false
+}
+
+func checkDependsOn(probs *problems, node *yaml.Node, path string) {
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+
+	for i, item := range node.Content {
+		item = resolve(item)
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch item.Kind {
+		case yaml.ScalarNode:
+		case yaml.MappingNode:
+			if valueOf(item, "step").Value == "" {
+				probs.errorf(item, itemPath, "depends_on entries must have a step key")
+			}
+			if allowFailure := valueOf(item, "allow_failure"); allowFailure.Kind != 0 && !matches(allowFailure, "boolean") {
+				probs.errorf(allowFailure, itemPath+".allow_failure", "allow_failure must be a boolean")
+			}
+		default:
+			probs.errorf(item, itemPath, "depends_on entries must be a step key or a mapping")
+		}
+	}
+}
+
+// checkDependencies reports duplicate keys, depends_on keys which don't exist, and dependency cycles
+func checkDependencies(probs *problems, pipeline *Pipeline) {
+	keyed := make(map[string]*Step)
+	pipeline.Walk(func(step *Step, group *Step) {
+		if step.Key == "" {
+			return
+		}
+		if _, ok := keyed[step.Key]; ok {
+			probs.errorf(step.node, step.Path, "duplicate step key %q", step.Key)
+			return
+		}
+		keyed[step.Key] = step
+	})
+
+	pipeline.Walk(func(step *Step, group *Step) {
+		for _, key := range step.DependsOn {
+			if _, ok := keyed[key]; !ok {
+				probs.errorf(valueOf(step.node, "depends_on"), step.Path+".depends_on", "depends_on %q does not match any step key", key)
+			}
+		}
+	})
+
+	for _, cycle := range Cycles(pipeline) {
+		names := make([]string, len(cycle))
+		for i, step := range cycle {
+			names[i] = step.Key
+			if names[i] == "" {
+				names[i] = step.Path
+			}
+		}
+		names = append(names, names[0])
+		probs.errorf(cycle[0].node, cycle[0].Path, "dependency cycle: %s", strings.Join(names, " -> "))
+	}
+}
+
+// Cycles returns each set of keyed steps which depend on each other in a loop, through depends_on or
+// the wait and block steps between them. Steps in a group depend on whatever the group depends on, and
+// the group itself only finishes once all of its steps have.
+func Cycles(pipeline *Pipeline) [][]*Step {
+	keyed := make(map[string]*Step)
+	edges := make(map[*Step][]*Step)
+	var order []*Step
+
+	implicit := make(map[*Step][]*Step)
+	walkSequence(pipeline.Steps, func(step *Step, deps []*Step) {
+		implicit[step] = deps
+		edges[step] = append(edges[step], deps...)
+	})
+
+	pipeline.Walk(func(step *Step, group *Step) {
+		if step.Key != "" {
+			if _, ok := keyed[step.Key]; !ok {
+				keyed[step.Key] = step
+			}
+		}
+		order = append(order, step)
+	})
+
+	pipeline.Walk(func(step *Step, group *Step) {
+		deps := step.DependsOn
+		if group != nil {
+			deps = append(deps[:len(deps):len(deps)], group.DependsOn...)
+			edges[group] = append(edges[group], step)
+			edges[step] = append(edges[step], implicit[group]...)
+		}
+		for _, key := range deps {
+			if dep, ok := keyed[key]; ok {
+				edges[step] = append(edges[step], dep)
+			}
+		}
+	})
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		cycles [][]*Step
+		state  = make(map[*Step]int)
+		stack  []*Step
+		visit  func(*Step)
+	)
+
+	visit = func(step *Step) {
+		state[step] = visiting
+		stack = append(stack, step)
+
+		for _, dep := range edges[step] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycles = append(cycles, namedOnly(stack[i:]))
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[step] = visited
+	}
+
+	for _, step := range order {
+		if state[step] == unvisited {
+			visit(step)
+		}
+	}
+
+	return cycles
+}
+
+// namedOnly drops unkeyed steps other than wait and block steps from a cycle, which can only be part
+// of one through their group
+func namedOnly(steps []*Step) []*Step {
+	result := make([]*Step, 0, len(steps))
+	for _, step := range steps {
+		if step.Key != "" || step.Type == TypeWait || step.Type == TypeBlock {
+			result = append(result, step)
+		}
+	}
+	if len(result) == 0 {
+		return steps
+	}
+	return result
+}
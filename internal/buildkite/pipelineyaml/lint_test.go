@@ -0,0 +1,163 @@
+package pipelineyaml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintValid(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline := `
+env:
+  GO_VERSION: "1.24"
+steps:
+  - label: ":go: test"
+    key: test
+    command: go test ./...
+    parallelism: 4
+    timeout_in_minutes: ${TIMEOUT}
+    plugins:
+      - docker#v5.0.0:
+          image: golang
+  - wait
+  - block: "Release?"
+    key: approve
+    fields:
+      - text: Notes
+        key: notes
+  - group: ":rocket: deploy"
+    key: deploy
+    depends_on:
+      - approve
+      - step: test
+        allow_failure: true
+    steps:
+      - trigger: deploy-pipeline
+        build:
+          branch: main
+      - input: "Confirm"
+`
+
+	assert.Empty(Lint([]byte(pipeline)))
+}
+
+func TestLintProblems(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline := `steps:
+  - command: make
+    key: build
+    parallelism: lots
+    timout_in_minutes: 10
+  - command: make test
+    key: build
+    depends_on: missing
+  - trigger: other
+    command: make
+  - label: nothing
+  - group: outer
+    steps:
+      - group: inner
+        steps: []
+  - waitt
+`
+
+	var messages []string
+	for _, p := range Lint([]byte(pipeline)) {
+		messages = append(messages, p.Severity+" "+p.String())
+	}
+
+	assert.Equal([]string{
+		"error 4:18: parallelism must be an integer",
+		"warning 5:5: unknown key \"timout_in_minutes\" for a command step",
+		"error 6:5: duplicate step key \"build\"",
+		"error 8:17: depends_on \"missing\" does not match any step key",
+		"error 10:5: step has both trigger and command keys",
+		"error 11:5: step has no type, expected one of command, wait, block, input, trigger or group",
+		"error 14:9: groups can't be nested",
+		"error 16:5: unknown step \"waitt\", expected wait, block or input",
+	}, messages)
+}
+
+func TestLintCycles(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline := `steps:
+  - command: a
+    key: a
+    depends_on: c
+  - command: b
+    key: b
+    depends_on: a
+  - command: c
+    key: c
+    depends_on: [b]
+  - group: g
+    key: g
+    steps:
+      - command: d
+        depends_on: g
+  - command: e
+    key: e
+    depends_on: e
+`
+
+	var messages []string
+	for _, p := range Lint([]byte(pipeline)) {
+		messages = append(messages, p.String())
+	}
+
+	assert.Equal([]string{
+		"2:5: dependency cycle: a -> c -> b -> a",
+		"11:5: dependency cycle: g -> g",
+		"16:5: dependency cycle: e -> e",
+	}, messages)
+}
+
+func TestLintCyclesThroughWait(t *testing.T) {
+	assert := require.New(t)
+
+	pipeline := `steps:
+  - command: lint
+    key: lint
+    depends_on: test
+  - wait
+  - command: test
+    key: test
+  - command: setup
+    key: setup
+    depends_on: deploy-us
+  - block: Deploy?
+  - group: Deploy
+    steps:
+      - command: deploy
+        key: deploy-us
+  - command: notify
+    depends_on: lint
+`
+
+	var messages []string
+	for _, p := range Lint([]byte(pipeline)) {
+		messages = append(messages, p.String())
+	}
+
+	// a step can't depend on one which waits for it through a wait or block step
+	assert.Equal([]string{
+		"2:5: dependency cycle: lint -> test -> steps[1] -> lint",
+		"8:5: dependency cycle: setup -> deploy-us -> steps[4] -> setup",
+	}, messages)
+}
+
+func TestLintInvalidYAML(t *testing.T) {
+	assert := require.New(t)
+
+	problems := Lint([]byte("steps:\n  - command: [\n"))
+	assert.Len(problems, 1)
+	assert.Equal(SeverityError, problems[0].Severity)
+	assert.Equal(2, problems[0].Line)
+
+	problems = Lint([]byte("env:\n  FOO: bar\n"))
+	assert.Equal([]Problem{{Line: 1, Column: 1, Severity: SeverityError, Message: "missing steps"}}, problems)
+}
@@ -0,0 +1,330 @@
+package pipelineyaml
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step types
+const (
+	TypeCommand = "command"
+	TypeWait    = "wait"
+	TypeBlock   = "block"
+	TypeInput   = "input"
+	TypeTrigger = "trigger"
+	TypeGroup   = "group"
+)
+
+// typeKeys maps the keys which identify a step to its type, including the legacy aliases
+var typeKeys = map[string]string{
+	"command":  TypeCommand,
+	"commands": TypeCommand,
+	"script":   TypeCommand,
+	"wait":     TypeWait,
+	"waiter":   TypeWait,
+	"block":    TypeBlock,
+	"manual":   TypeBlock,
+	"input":    TypeInput,
+	"trigger":  TypeTrigger,
+	"group":    TypeGroup,
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// Pipeline is the parsed step structure of a pipeline.yml
+type Pipeline struct {
+	Steps []*Step
+
+	root *yaml.Node
+}
+
+// Step is a single step, or a group and the steps within it
+type Step struct {
+	Type                   string
+	Key                    string
+	Label                  string
+	DependsOn              []string
+	AllowDependencyFailure bool
+	Line                   int
+	Column                 int
+	Path                   string
+	Steps                  []*Step
+
+	node *yaml.Node
+}
+
+// Name is the key or label used to refer to the step in messages and graphs
+func (s *Step) Name() string {
+	switch {
+	case s.Key != "":
+		return s.Key
+	case s.Label != "":
+		return s.Label
+	default:
+		return s.Type
+	}
+}
+
+// Walk calls fn for each step in order, including those within groups
+func (p *Pipeline) Walk(fn func(step *Step, group *Step)) {
+	for _, step := range p.Steps {
+		fn(step, nil)
+		for _, child := range step.Steps {
+			fn(child, step)
+		}
+	}
+}
+
+// Problem is an error or warning found in a pipeline, positioned at the offending node
+type Problem struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Column == 0 {
+		// yaml syntax errors only come with a line
+		return fmt.Sprintf("%d: %s", p.Line, p.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+type problems []Problem
+
+func (p *problems) add(severity string, node *yaml.Node, path, format string, args ...any) {
+	*p = append(*p, Problem{
+		Line:     node.Line,
+		Column:   node.Column,
+		Path:     path,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (p *problems) errorf(node *yaml.Node, path, format string, args ...any) {
+	p.add(SeverityError, node, path, format, args...)
+}
+
+func (p *problems) warnf(node *yaml.Node, path, format string, args ...any) {
+	p.add(SeverityWarning, node, path, format, args...)
+}
+
+// Parse reads the steps out of a pipeline.yml, which is either a mapping with a steps key or a bare
+// list of steps. Steps which can't be understood are reported as problems and left out of the pipeline.
+func Parse(data []byte) (*Pipeline, []Problem) {
+	var probs problems
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 0
+		message := err.Error()
+		if m := yamlErrorLineRegexp.FindStringSubmatch(message); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			message = message[len(m[0]):]
+		}
+		return nil, []Problem{{Line: line, Severity: SeverityError, Message: message}}
+	}
+
+	if len(doc.Content) == 0 {
+		return nil, []Problem{{Line: 1, Column: 1, Severity: SeverityError, Message: "pipeline is empty"}}
+	}
+
+	root := resolve(doc.Content[0])
+	steps := root
+	if root.Kind == yaml.MappingNode {
+		steps = nil
+		for _, pair := range pairs(root) {
+			if pair.key.Value == "steps" {
+				steps = pair.value
+			}
+		}
+		if steps == nil {
+			probs.errorf(root, "", "missing steps")
+			return nil, probs
+		}
+	}
+
+	pipeline := &Pipeline{root: root}
+	if steps.Kind != yaml.SequenceNode {
+		probs.errorf(steps, "steps", "steps must be a list")
+		return pipeline, probs
+	}
+
+	for i, node := range steps.Content {
+		step := parseStep(&probs, resolve(node), fmt.Sprintf("steps[%d]", i))
+		if step == nil {
+			continue
+		}
+
+		if step.Type == TypeGroup {
+			step.Steps = parseGroupSteps(&probs, step)
+		}
+		pipeline.Steps = append(pipeline.Steps, step)
+	}
+
+	return pipeline, probs
+}
+
+func parseGroupSteps(probs *problems, group *Step) []*Step {
+	var children []*Step
+	for _, pair := range pairs(group.node) {
+		if pair.key.Value != "steps" {
+			continue
+		}
+
+		if pair.value.Kind != yaml.SequenceNode {
+			// reported by the schema check
+			return nil
+		}
+
+		for i, node := range pair.value.Content {
+			path := fmt.Sprintf("%s.steps[%d]", group.Path, i)
+			child := parseStep(probs, resolve(node), path)
+			if child == nil {
+				continue
+			}
+			if child.Type == TypeGroup {
+				probs.errorf(child.node, path, "groups can't be nested")
+				continue
+			}
+			children = append(children, child)
+		}
+		return children
+	}
+
+	probs.errorf(group.node, group.Path, "group has no steps")
+	return nil
+}
+
+func parseStep(probs *problems, node *yaml.Node, path string) *Step {
+	step := &Step{
+		Line:   node.Line,
+		Column: node.Column,
+		Path:   path,
+		node:   node,
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		stepType, ok := typeKeys[node.Value]
+		if !ok || (stepType != TypeWait && stepType != TypeBlock && stepType != TypeInput) {
+			probs.errorf(node, path, "unknown step %q, expected wait, block or input", node.Value)
+			return nil
+		}
+		step.Type = stepType
+		return step
+	case yaml.MappingNode:
+	default:
+		probs.errorf(node, path, "step must be a mapping")
+		return nil
+	}
+
+	var typeKey string
+	for _, pair := range pairs(node) {
+		key := pair.key.Value
+		if stepType, ok := typeKeys[key]; ok {
+			if step.Type != "" && stepType != step.Type {
+				probs.errorf(pair.key, path+"."+key, "step has both %s and %s keys", typeKey, key)
+				continue
+			}
+			step.Type, typeKey = stepType, key
+		}
+
+		switch key {
+		case "key", "identifier", "id":
+			step.Key = pair.value.Value
+		case "label", "name":
+			if step.Label == "" {
+				step.Label = pair.value.Value
+			}
+		case "depends_on":
+			step.DependsOn = dependsOn(pair.value)
+		case "allow_dependency_failure":
+			step.AllowDependencyFailure = pair.value.Value == "true"
+		}
+	}
+
+	if step.Type == "" {
+		probs.errorf(node, path, "step has no type, expected one of command, wait, block, input, trigger or group")
+		return nil
+	}
+
+	// the type key doubles as the label of block, input, trigger and group steps
+	if step.Label == "" && step.Type != TypeCommand && step.Type != TypeWait {
+		step.Label = valueOf(node, typeKey).Value
+	}
+
+	return step
+}
+
+// dependsOn reads the keys from a depends_on value, which can be a key, a list of keys, or a list of
+// mappings with a step key
+func dependsOn(node *yaml.Node) []string {
+	node = resolve(node)
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			return nil
+		}
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		var keys []string
+		for _, item := range node.Content {
+			item = resolve(item)
+			switch item.Kind {
+			case yaml.ScalarNode:
+				keys = append(keys, item.Value)
+			case yaml.MappingNode:
+				if step := valueOf(item, "step"); step.Value != "" {
+					keys = append(keys, step.Value)
+				}
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+type pair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// pairs returns the key and value nodes of a mapping, skipping merge keys
+func pairs(node *yaml.Node) []pair {
+	var result []pair
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "<<" {
+			continue
+		}
+		result = append(result, pair{key: node.Content[i], value: resolve(node.Content[i+1])})
+	}
+	return result
+}
+
+func valueOf(node *yaml.Node, key string) *yaml.Node {
+	for _, pair := range pairs(node) {
+		if pair.key.Value == key {
+			return pair.value
+		}
+	}
+	return &yaml.Node{}
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,503 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// recentBuildsForHealth is the number of builds pre-fetched when summarising pipeline health
+const recentBuildsForHealth = 30
+
+// maxBacklogJobs caps how many waiting jobs are pre-fetched when explaining a queue backlog
+const maxBacklogJobs = 50
+
+// FailedJobSummary is a compact view of a job that did not pass, used to keep prompt payloads small
+type FailedJobSummary struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	StepKey    string `json:"step_key,omitempty"`
+	State      string `json:"state"`
+	ExitStatus *int   `json:"exit_status,omitempty"`
+	SoftFailed bool   `json:"soft_failed,omitempty"`
+	WebURL     string `json:"web_url,omitempty"`
+}
+
+// WaitingJob is a job which is ready to run on a cluster queue but hasn't been picked up by an agent
+type WaitingJob struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name,omitempty"`
+	QueueID         string   `json:"queue_id,omitempty"`
+	AgentQueryRules []string `json:"agent_query_rules,omitempty"`
+	PipelineSlug    string   `json:"pipeline_slug,omitempty"`
+	BuildNumber     int      `json:"build_number"`
+	WaitingSeconds  int      `json:"waiting_seconds"`
+	WebURL          string   `json:"web_url,omitempty"`
+}
+
+// QueueBacklog is how many jobs are waiting on a cluster queue and how long the oldest has waited
+type QueueBacklog struct {
+	QueueID           string `json:"queue_id"`
+	Key               string `json:"key"`
+	DispatchPaused    bool   `json:"dispatch_paused"`
+	WaitingJobs       int    `json:"waiting_jobs"`
+	OldestWaitSeconds int    `json:"oldest_wait_seconds,omitempty"`
+}
+
+func DebugFailedBuildPrompt(ctx context.Context, builds BuildsClient, annotations AnnotationsClient) (prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	return mcp.NewPrompt("debug_failed_build",
+			mcp.WithPromptDescription("Investigate why a build failed, starting from its failed jobs and error annotations"),
+			mcp.WithArgument("org",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithArgument("pipeline_slug",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The slug of the pipeline"),
+			),
+			mcp.WithArgument("build_number",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The number of the build"),
+			),
+		), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.DebugFailedBuildPrompt")
+			defer span.End()
+
+			args, err := requirePromptArguments(request, "org", "pipeline_slug", "build_number")
+			if err != nil {
+				return nil, err
+			}
+			org, pipelineSlug, buildNumber := args["org"], args["pipeline_slug"], args["build_number"]
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
+			)
+
+			build, resp, err := builds.Get(ctx, org, pipelineSlug, buildNumber, &buildkite.BuildGetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get build: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to get build: unexpected status %d", resp.StatusCode)
+			}
+
+			failedJobs := make([]FailedJobSummary, 0)
+			for _, job := range build.Jobs {
+				if !isFailedJob(job) {
+					continue
+				}
+				failedJobs = append(failedJobs, summarizeFailedJob(job))
+			}
+
+			errorAnnotations := make([]buildkite.Annotation, 0)
+			buildAnnotations, resp, err := annotations.ListByBuild(ctx, org, pipelineSlug, buildNumber, &buildkite.AnnotationListOptions{
+				ListOptions: buildkite.ListOptions{PerPage: 100},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list annotations: %w", err)
+			}
+			if resp.StatusCode == http.StatusOK {
+				for _, annotation := range buildAnnotations {
+					if annotation.Style == "error" || annotation.Style == "warning" {
+						errorAnnotations = append(errorAnnotations, annotation)
+					}
+				}
+			}
+
+			data, err := json.Marshal(map[string]any{
+				"build": map[string]any{
+					"number":  build.Number,
+					"state":   build.State,
+					"branch":  build.Branch,
+					"commit":  build.Commit,
+					"message": build.Message,
+					"web_url": build.WebURL,
+				},
+				"failed_jobs":       failedJobs,
+				"error_annotations": errorAnnotations,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal build details: %w", err)
+			}
+
+			return newPromptResult("Investigate why a build failed",
+				fmt.Sprintf(`Build %s of pipeline %s/%s has failed. Explain the most likely root cause of the failure and suggest a fix.
+
+Use get_job_logs to read the output of each failed job below, and get_build_test_engine_runs with get_failed_executions if the build reports to Test Engine. Ignore soft failures unless they explain the hard failures.
+
+Build details:
+%s`, buildNumber, org, pipelineSlug, data)), nil
+		}
+}
+
+func InvestigateFlakyTestPrompt(ctx context.Context, tests TestsClient, testRuns TestRunsClient) (prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	return mcp.NewPrompt("investigate_flaky_test",
+			mcp.WithPromptDescription("Investigate whether a Test Engine test is flaky and what causes it to fail intermittently"),
+			mcp.WithArgument("org",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The organization slug for the owner of the test suite"),
+			),
+			mcp.WithArgument("test_suite_slug",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The slug of the test suite"),
+			),
+			mcp.WithArgument("test_id",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The ID of the test"),
+			),
+		), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.InvestigateFlakyTestPrompt")
+			defer span.End()
+
+			args, err := requirePromptArguments(request, "org", "test_suite_slug", "test_id")
+			if err != nil {
+				return nil, err
+			}
+			org, testSuiteSlug, testID := args["org"], args["test_suite_slug"], args["test_id"]
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("test_suite_slug", testSuiteSlug),
+				attribute.String("test_id", testID),
+			)
+
+			test, resp, err := tests.Get(ctx, org, testSuiteSlug, testID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get test: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to get test: unexpected status %d", resp.StatusCode)
+			}
+
+			recentRuns, err := listTestRunsUpTo(ctx, testRuns, org, testSuiteSlug, defaultTestHistoryRuns)
+			if err != nil {
+				return nil, err
+			}
+
+			// runs which are still going may not have reported all of their failures yet
+			finished := make([]buildkite.TestRun, 0, len(recentRuns))
+			for _, run := range recentRuns {
+				if run.State == "" || run.State == "finished" {
+					finished = append(finished, run)
+				}
+			}
+
+			analyzed, failures, errs := getFailedExecutions(ctx, testRuns, org, testSuiteSlug, finished)
+			history := testHistory(testID, analyzed, failures)
+
+			recentFailures := make([]TestExecution, 0, history.Failures)
+			for _, execution := range history.Executions {
+				if execution.Result == TestResultFailed {
+					recentFailures = append(recentFailures, execution)
+				}
+			}
+
+			data, err := json.Marshal(map[string]any{
+				"test":                 test,
+				"runs_analyzed":        history.RunsAnalyzed,
+				"runs_skipped":         len(finished) - len(analyzed),
+				"failures":             history.Failures,
+				"failure_rate_percent": history.FailureRatePercent,
+				"failing_since":        history.FailingSince,
+				"recent_failures":      recentFailures,
+				"errors":               errs,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal test: %w", err)
+			}
+
+			return newPromptResult("Investigate a flaky test",
+				fmt.Sprintf(`Determine whether the test below in suite %s/%s is flaky, and if so, why.
+
+Its failures in the suite's %d most recent finished runs are included below, newest first. Use get_failed_executions with include_failure_expanded on those runs for the full failure output. Compare failures on the same commit, look for timing, ordering or shared-state causes, and recommend a fix or quarantine.
+
+Test details:
+%s`, org, testSuiteSlug, history.RunsAnalyzed, data)), nil
+		}
+}
+
+func SummarizePipelineHealthPrompt(ctx context.Context, pipelines PipelinesClient, builds BuildsClient) (prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	return mcp.NewPrompt("summarize_pipeline_health",
+			mcp.WithPromptDescription("Summarise the recent health of a pipeline from its latest builds"),
+			mcp.WithArgument("org",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The organization slug for the owner of the pipeline"),
+			),
+			mcp.WithArgument("pipeline_slug",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The slug of the pipeline"),
+			),
+		), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.SummarizePipelineHealthPrompt")
+			defer span.End()
+
+			args, err := requirePromptArguments(request, "org", "pipeline_slug")
+			if err != nil {
+				return nil, err
+			}
+			org, pipelineSlug := args["org"], args["pipeline_slug"]
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("pipeline_slug", pipelineSlug),
+			)
+
+			pipeline, resp, err := pipelines.Get(ctx, org, pipelineSlug)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pipeline: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to get pipeline: unexpected status %d", resp.StatusCode)
+			}
+
+			recentBuilds, resp, err := builds.ListByPipeline(ctx, org, pipelineSlug, &buildkite.BuildsListOptions{
+				ExcludeJobs:     true,
+				ExcludePipeline: true,
+				ListOptions:     buildkite.ListOptions{PerPage: recentBuildsForHealth},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list builds: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to list builds: unexpected status %d", resp.StatusCode)
+			}
+
+			byState := make(map[string]int)
+			summaries := make([]map[string]any, 0, len(recentBuilds))
+			for _, build := range recentBuilds {
+				byState[build.State]++
+				summaries = append(summaries, map[string]any{
+					"number":      build.Number,
+					"state":       build.State,
+					"branch":      build.Branch,
+					"created_at":  build.CreatedAt,
+					"finished_at": build.FinishedAt,
+				})
+			}
+
+			data, err := json.Marshal(map[string]any{
+				"pipeline": map[string]any{
+					"name":           pipeline.Name,
+					"slug":           pipeline.Slug,
+					"default_branch": pipeline.DefaultBranch,
+					"web_url":        pipeline.WebURL,
+				},
+				"builds_by_state": byState,
+				"recent_builds":   summaries,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal pipeline health: %w", err)
+			}
+
+			return newPromptResult("Summarise pipeline health",
+				fmt.Sprintf(`Summarise the health of pipeline %s/%s using its %d most recent builds below.
+
+Report the pass rate, whether the default branch is currently green, any streaks of failures and typical build durations. Call out branches that fail disproportionately and use get_build on individual failures if more detail is needed, or pipeline_metrics for trends over a longer window.
+
+Pipeline details:
+%s`, org, pipelineSlug, len(recentBuilds), data)), nil
+		}
+}
+
+func ExplainQueueBacklogPrompt(ctx context.Context, clusters ClustersClient, queues ClusterQueuesClient, builds BuildsClient) (prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	return mcp.NewPrompt("explain_queue_backlog",
+			mcp.WithPromptDescription("Explain why jobs are waiting in the queues of a cluster"),
+			mcp.WithArgument("org",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The organization slug for the owner of the cluster"),
+			),
+			mcp.WithArgument("cluster_id",
+				mcp.RequiredArgument(),
+				mcp.ArgumentDescription("The id of the cluster"),
+			),
+		), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.ExplainQueueBacklogPrompt")
+			defer span.End()
+
+			args, err := requirePromptArguments(request, "org", "cluster_id")
+			if err != nil {
+				return nil, err
+			}
+			org, clusterID := args["org"], args["cluster_id"]
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("cluster_id", clusterID),
+			)
+
+			cluster, resp, err := clusters.Get(ctx, org, clusterID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get cluster: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to get cluster: unexpected status %d", resp.StatusCode)
+			}
+
+			clusterQueues, resp, err := queues.List(ctx, org, clusterID, &buildkite.ClusterQueuesListOptions{
+				ListOptions: buildkite.ListOptions{PerPage: 100},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list cluster queues: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to list cluster queues: unexpected status %d", resp.StatusCode)
+			}
+
+			// jobs waiting for an agent belong to builds which haven't finished yet
+			activeBuilds, resp, err := builds.ListByOrg(ctx, org, &buildkite.BuildsListOptions{
+				State:       []string{"scheduled", "running", "failing"},
+				ListOptions: buildkite.ListOptions{PerPage: 100},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list builds: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to list builds: unexpected status %d", resp.StatusCode)
+			}
+
+			backlogs, waitingJobs := queueBacklogs(clusterID, clusterQueues, activeBuilds, time.Now())
+
+			waitingJobsTruncated := len(waitingJobs) > maxBacklogJobs || resp.NextPage != 0
+			if len(waitingJobs) > maxBacklogJobs {
+				waitingJobs = waitingJobs[:maxBacklogJobs]
+			}
+
+			data, err := json.Marshal(map[string]any{
+				"cluster":                cluster,
+				"queues":                 clusterQueues,
+				"backlog_by_queue":       backlogs,
+				"waiting_jobs":           waitingJobs,
+				"waiting_jobs_truncated": waitingJobsTruncated,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cluster queues: %w", err)
+			}
+
+			return newPromptResult("Explain a queue backlog",
+				fmt.Sprintf(`Jobs are backing up in cluster %s of organization %s. Explain the likely cause of the backlog and how to relieve it.
+
+The cluster's queues are below with the jobs waiting for an agent on each, longest waiting first. Check the queues with the most and oldest waiting jobs for paused dispatch and hosted agent capacity, use list_agents to see whether agents are connected to them, and check the agent query rules of waiting jobs for targets no agent matches.
+
+Cluster details:
+%s`, clusterID, org, data)), nil
+		}
+}
+
+// queueBacklogs finds the jobs of builds which are waiting for an agent on a cluster's queues, longest
+// waiting first, and totals them by queue
+func queueBacklogs(clusterID string, queues []buildkite.ClusterQueue, builds []buildkite.Build, now time.Time) ([]QueueBacklog, []WaitingJob) {
+	backlogs := make([]QueueBacklog, 0, len(queues))
+	byQueue := make(map[string]*QueueBacklog, len(queues))
+	for _, queue := range queues {
+		backlogs = append(backlogs, QueueBacklog{QueueID: queue.ID, Key: queue.Key, DispatchPaused: queue.DispatchPaused})
+	}
+	for i := range backlogs {
+		byQueue[backlogs[i].QueueID] = &backlogs[i]
+	}
+
+	waitingJobs := make([]WaitingJob, 0)
+	for _, build := range builds {
+		for _, job := range build.Jobs {
+			if job.State != "scheduled" {
+				continue
+			}
+			backlog := byQueue[job.ClusterQueueID]
+			if backlog == nil && job.ClusterID != clusterID {
+				continue
+			}
+
+			waiting := WaitingJob{
+				ID:              job.ID,
+				Name:            job.Name,
+				QueueID:         job.ClusterQueueID,
+				AgentQueryRules: job.AgentQueryRules,
+				BuildNumber:     build.Number,
+				WebURL:          job.WebURL,
+			}
+			if build.Pipeline != nil {
+				waiting.PipelineSlug = build.Pipeline.Slug
+			}
+			if since := jobWaitingSince(job); since != nil {
+				waiting.WaitingSeconds = int(now.Sub(since.Time).Seconds())
+			}
+			waitingJobs = append(waitingJobs, waiting)
+
+			if backlog != nil {
+				backlog.WaitingJobs++
+				backlog.OldestWaitSeconds = max(backlog.OldestWaitSeconds, waiting.WaitingSeconds)
+			}
+		}
+	}
+
+	slices.SortStableFunc(waitingJobs, func(a, b WaitingJob) int {
+		return b.WaitingSeconds - a.WaitingSeconds
+	})
+
+	return backlogs, waitingJobs
+}
+
+// jobWaitingSince is when a job became ready to run, falling back to when it was scheduled or created
+func jobWaitingSince(job buildkite.Job) *buildkite.Timestamp {
+	switch {
+	case job.RunnableAt != nil:
+		return job.RunnableAt
+	case job.ScheduledAt != nil:
+		return job.ScheduledAt
+	default:
+		return job.CreatedAt
+	}
+}
+
+// isFailedJob reports whether a job failed, including soft failures which don't fail the build
+func isFailedJob(job buildkite.Job) bool {
+	switch job.State {
+	case "failed", "timed_out", "broken":
+		return true
+	}
+	return job.SoftFailed
+}
+
+func summarizeFailedJob(job buildkite.Job) FailedJobSummary {
+	return FailedJobSummary{
+		ID:         job.ID,
+		Name:       job.Name,
+		StepKey:    job.StepKey,
+		State:      job.State,
+		ExitStatus: job.ExitStatus,
+		SoftFailed: job.SoftFailed,
+		WebURL:     job.WebURL,
+	}
+}
+
+// requirePromptArguments returns the named prompt arguments, failing if any are missing or empty
+func requirePromptArguments(request mcp.GetPromptRequest, names ...string) (map[string]string, error) {
+	args := make(map[string]string, len(names))
+	for _, name := range names {
+		value := request.Params.Arguments[name]
+		if value == "" {
+			return nil, fmt.Errorf("required argument %q not found", name)
+		}
+		args[name] = value
+	}
+	return args, nil
+}
+
+func newPromptResult(description, text string) *mcp.GetPromptResult {
+	return &mcp.GetPromptResult{
+		Description: description,
+		Messages: []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		},
+	}
+}
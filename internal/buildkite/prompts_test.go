@@ -0,0 +1,348 @@
+package buildkite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func createPromptRequest(t *testing.T, args map[string]string) mcp.GetPromptRequest {
+	t.Helper()
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func getPromptText(t *testing.T, result *mcp.GetPromptResult) string {
+	t.Helper()
+	require.Len(t, result.Messages, 1)
+	textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+	require.True(t, ok, "expected text content")
+	return textContent.Text
+}
+
+func TestDebugFailedBuildPrompt(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	exitStatus := 1
+
+	builds := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			return buildkite.Build{
+					Number: 42,
+					State:  "failed",
+					Branch: "main",
+					Jobs: []buildkite.Job{
+						{ID: "job1", Name: "lint", State: "passed"},
+						{ID: "job2", Name: "test", State: "failed", ExitStatus: &exitStatus},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	annotations := &MockAnnotationsClient{
+		ListByBuildFunc: func(ctx context.Context, org, pipelineSlug, buildNumber string, opts *buildkite.AnnotationListOptions) ([]buildkite.Annotation, *buildkite.Response, error) {
+			return []buildkite.Annotation{
+					{ID: "1", Style: "info", BodyHTML: "All good"},
+					{ID: "2", Style: "error", BodyHTML: "Tests failed"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	prompt, handler := DebugFailedBuildPrompt(ctx, builds, annotations)
+	assert.Equal("debug_failed_build", prompt.Name)
+	assert.Len(prompt.Arguments, 3)
+
+	result, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+		"build_number":  "42",
+	}))
+	assert.NoError(err)
+
+	text := getPromptText(t, result)
+	assert.Contains(text, `"failed_jobs":[{"id":"job2","name":"test","state":"failed","exit_status":1}]`)
+	assert.Contains(text, `"body_html":"Tests failed"`)
+	assert.NotContains(text, `"lint"`)
+	assert.NotContains(text, "All good")
+}
+
+func TestDebugFailedBuildPromptMissingArgument(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	_, handler := DebugFailedBuildPrompt(ctx, &MockBuildsClient{}, &MockAnnotationsClient{})
+
+	_, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":           "org",
+		"pipeline_slug": "pipeline",
+	}))
+	assert.EqualError(err, `required argument "build_number" not found`)
+}
+
+func TestExplainQueueBacklogPrompt(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	clusters := &mockClustersClient{
+		GetFunc: func(ctx context.Context, org, id string) (buildkite.Cluster, *buildkite.Response, error) {
+			return buildkite.Cluster{
+					ID:   "cluster-id",
+					Name: "Default",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	queues := &mockClusterQueuesClient{
+		ListFunc: func(ctx context.Context, org, clusterID string, opts *buildkite.ClusterQueuesListOptions) ([]buildkite.ClusterQueue, *buildkite.Response, error) {
+			return []buildkite.ClusterQueue{
+					{ID: "queue-id", Key: "linux", DispatchPaused: true},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	waitingSince := &buildkite.Timestamp{Time: time.Now().Add(-10 * time.Minute)}
+	builds := &MockBuildsClient{
+		ListByOrgFunc: func(ctx context.Context, org string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error) {
+			assert.Equal([]string{"scheduled", "running", "failing"}, opt.State)
+			return []buildkite.Build{
+					{
+						Number:   7,
+						Pipeline: &buildkite.Pipeline{Slug: "app"},
+						Jobs: []buildkite.Job{
+							{ID: "job1", Name: "test", State: "scheduled", ClusterID: "cluster-id", ClusterQueueID: "queue-id", AgentQueryRules: []string{"queue=linux"}, RunnableAt: waitingSince},
+							{ID: "job2", Name: "lint", State: "running", ClusterID: "cluster-id", ClusterQueueID: "queue-id"},
+							{ID: "job3", Name: "deploy", State: "scheduled", ClusterID: "other-cluster", ClusterQueueID: "other-queue"},
+						},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	prompt, handler := ExplainQueueBacklogPrompt(ctx, clusters, queues, builds)
+	assert.Equal("explain_queue_backlog", prompt.Name)
+
+	result, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":        "org",
+		"cluster_id": "cluster-id",
+	}))
+	assert.NoError(err)
+
+	text := getPromptText(t, result)
+	assert.Contains(text, "cluster cluster-id of organization org")
+	assert.Contains(text, `"key":"linux"`)
+	assert.Contains(text, `"dispatch_paused":true`)
+	assert.Contains(text, `"backlog_by_queue":[{"queue_id":"queue-id","key":"linux","dispatch_paused":true,"waiting_jobs":1,"oldest_wait_seconds":600}]`)
+	assert.Contains(text, `"waiting_jobs":[{"id":"job1","name":"test","queue_id":"queue-id","agent_query_rules":["queue=linux"],"pipeline_slug":"app","build_number":7,"waiting_seconds":600}]`)
+	assert.Contains(text, `"waiting_jobs_truncated":false`)
+}
+
+func TestQueueBacklogs(t *testing.T) {
+	assert := require.New(t)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: now.Add(-time.Duration(minutes) * time.Minute)}
+	}
+
+	queues := []buildkite.ClusterQueue{{ID: "q1", Key: "linux"}, {ID: "q2", Key: "mac"}}
+	builds := []buildkite.Build{
+		{Number: 1, Jobs: []buildkite.Job{
+			{ID: "a", State: "scheduled", ClusterQueueID: "q1", RunnableAt: at(1)},
+			{ID: "b", State: "scheduled", ClusterQueueID: "q1", ScheduledAt: at(5)},
+		}},
+		{Number: 2, Jobs: []buildkite.Job{
+			{ID: "c", State: "scheduled", ClusterID: "cluster", CreatedAt: at(3)},
+			{ID: "d", State: "waiting", ClusterQueueID: "q2"},
+		}},
+	}
+
+	backlogs, waiting := queueBacklogs("cluster", queues, builds, now)
+	assert.Equal([]QueueBacklog{
+		{QueueID: "q1", Key: "linux", WaitingJobs: 2, OldestWaitSeconds: 300},
+		{QueueID: "q2", Key: "mac"},
+	}, backlogs)
+
+	ids := make([]string, 0, len(waiting))
+	for _, job := range waiting {
+		ids = append(ids, job.ID)
+	}
+	assert.Equal([]string{"b", "c", "a"}, ids)
+}
+
+func TestInvestigateFlakyTestPrompt(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	tests := &MockTestsClient{
+		GetFunc: func(ctx context.Context, org, slug, testID string) (buildkite.Test, *buildkite.Response, error) {
+			return buildkite.Test{
+					ID:   "test-1",
+					Name: "User signs up",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	testRuns := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			return []buildkite.TestRun{
+					{ID: "run-3", State: "running"},
+					{ID: "run-2", State: "finished", Result: "failed", CommitSHA: "bbb"},
+					{ID: "run-1", State: "finished", Result: "passed", CommitSHA: "aaa"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			assert.NotEqual("run-3", runID, "failures of unfinished runs shouldn't be read")
+
+			var executions []buildkite.FailedExecution
+			if runID == "run-2" {
+				executions = []buildkite.FailedExecution{
+					{TestID: "test-1", FailureReason: "timed out waiting for the database"},
+					{TestID: "test-2", FailureReason: "unrelated"},
+				}
+			}
+			return executions, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+	}
+
+	prompt, handler := InvestigateFlakyTestPrompt(ctx, tests, testRuns)
+	assert.Equal("investigate_flaky_test", prompt.Name)
+	assert.Len(prompt.Arguments, 3)
+
+	result, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"test_id":         "test-1",
+	}))
+	assert.NoError(err)
+
+	text := getPromptText(t, result)
+	assert.Contains(text, "suite org/suite")
+	assert.Contains(text, "2 most recent finished runs")
+	assert.Contains(text, `"name":"User signs up"`)
+	assert.Contains(text, `"runs_analyzed":2`)
+	assert.Contains(text, `"failures":1`)
+	assert.Contains(text, `"failure_rate_percent":50`)
+	assert.Contains(text, `"recent_failures":[{"run_id":"run-2","result":"failed","commit_sha":"bbb","failure_reason":"timed out waiting for the database"}]`)
+	assert.NotContains(text, "unrelated")
+}
+
+func TestInvestigateFlakyTestPromptMissingArgument(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	_, handler := InvestigateFlakyTestPrompt(ctx, &MockTestsClient{}, &MockTestRunsClient{})
+
+	_, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":     "org",
+		"test_id": "test-1",
+	}))
+	assert.EqualError(err, `required argument "test_suite_slug" not found`)
+}
+
+func TestSummarizePipelineHealthPrompt(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	pipelines := &MockPipelinesClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string) (buildkite.Pipeline, *buildkite.Response, error) {
+			return buildkite.Pipeline{
+					Name:          "App",
+					Slug:          "app",
+					DefaultBranch: "main",
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	builds := &MockBuildsClient{
+		ListByPipelineFunc: func(ctx context.Context, org string, pipeline string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error) {
+			assert.Equal(recentBuildsForHealth, opt.PerPage)
+			assert.True(opt.ExcludeJobs)
+			return []buildkite.Build{
+					{Number: 3, State: "failed", Branch: "main"},
+					{Number: 2, State: "passed", Branch: "main"},
+					{Number: 1, State: "passed", Branch: "feature"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	prompt, handler := SummarizePipelineHealthPrompt(ctx, pipelines, builds)
+	assert.Equal("summarize_pipeline_health", prompt.Name)
+	assert.Len(prompt.Arguments, 2)
+
+	result, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org":           "org",
+		"pipeline_slug": "app",
+	}))
+	assert.NoError(err)
+
+	text := getPromptText(t, result)
+	assert.Contains(text, "pipeline org/app using its 3 most recent builds")
+	assert.Contains(text, `"default_branch":"main"`)
+	assert.Contains(text, `"builds_by_state":{"failed":1,"passed":2}`)
+	assert.Contains(text, `{"branch":"feature","created_at":null,"finished_at":null,"number":1,"state":"passed"}`)
+}
+
+func TestSummarizePipelineHealthPromptMissingArgument(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	_, handler := SummarizePipelineHealthPrompt(ctx, &MockPipelinesClient{}, &MockBuildsClient{})
+
+	_, err := handler(ctx, createPromptRequest(t, map[string]string{
+		"org": "org",
+	}))
+	assert.EqualError(err, `required argument "pipeline_slug" not found`)
+}
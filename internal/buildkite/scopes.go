@@ -0,0 +1,145 @@
+package buildkite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// API token scopes, see https://buildkite.com/docs/apis/managing-api-tokens#token-scopes
+const (
+	ScopeReadAgents        = "read_agents"
+	ScopeWriteAgents       = "write_agents"
+	ScopeReadArtifacts     = "read_artifacts"
+	ScopeReadBuilds        = "read_builds"
+	ScopeWriteBuilds       = "write_builds"
+	ScopeReadBuildLogs     = "read_build_logs"
+	ScopeReadClusters      = "read_clusters"
+	ScopeWriteClusters     = "write_clusters"
+	ScopeReadOrganizations = "read_organizations"
+	ScopeReadPipelines     = "read_pipelines"
+	ScopeWritePipelines    = "write_pipelines"
+	ScopeReadSuites        = "read_suites"
+	ScopeReadUser          = "read_user"
+)
+
+// toolScopes are the token scopes each tool needs to make the API requests it can't do without.
+// Requests a tool can do without, like diagnose_build's annotations, are left out so a token
+// without those scopes can still use the tool.
+var toolScopes = map[string][]string{
+	// Cluster tools
+	"get_cluster":    {ScopeReadClusters},
+	"list_clusters":  {ScopeReadClusters},
+	"create_cluster": {ScopeWriteClusters},
+	"update_cluster": {ScopeWriteClusters},
+
+	// Cluster token tools
+	"list_cluster_tokens":  {ScopeReadClusters},
+	"create_cluster_token": {ScopeWriteClusters},
+	"revoke_cluster_token": {ScopeWriteClusters},
+
+	// Queue tools
+	"get_cluster_queue":     {ScopeReadClusters},
+	"list_cluster_queues":   {ScopeReadClusters},
+	"create_cluster_queue":  {ScopeWriteClusters},
+	"update_cluster_queue":  {ScopeWriteClusters},
+	"pause_queue_dispatch":  {ScopeReadClusters, ScopeWriteClusters},
+	"resume_queue_dispatch": {ScopeReadClusters, ScopeWriteClusters},
+
+	// Pipeline tools
+	"get_pipeline":       {ScopeReadPipelines},
+	"list_pipelines":     {ScopeReadPipelines},
+	"create_pipeline":    {ScopeWritePipelines},
+	"update_pipeline":    {ScopeReadPipelines, ScopeWritePipelines},
+	"get_pipeline_graph": {ScopeReadPipelines},
+	"lint_pipeline_yaml": {},
+
+	// Build tools
+	"list_builds":                {ScopeReadBuilds},
+	"get_build":                  {ScopeReadBuilds},
+	"analyze_build_timing":       {ScopeReadBuilds},
+	"pipeline_metrics":           {ScopeReadBuilds},
+	"get_build_test_engine_runs": {ScopeReadBuilds},
+
+	// User tools
+	"current_user":            {ScopeReadUser},
+	"user_token_organization": {ScopeReadOrganizations},
+
+	// Job tools
+	"get_jobs":     {ScopeReadBuilds},
+	"get_job_logs": {ScopeReadBuildLogs},
+
+	// Diagnostic tools
+	"diagnose_build": {ScopeReadBuilds, ScopeReadBuildLogs},
+
+	// Agent tools
+	"list_agents": {ScopeReadAgents},
+	"get_agent":   {ScopeReadAgents},
+	"stop_agent":  {ScopeWriteAgents},
+
+	// Artifacts tools
+	"list_artifacts":              {ScopeReadArtifacts},
+	"get_artifact":                {ScopeReadArtifacts},
+	"list_artifact_archive":       {ScopeReadArtifacts},
+	"read_artifact_archive_entry": {ScopeReadArtifacts},
+	"get_junit_results":           {ScopeReadArtifacts},
+	"get_coverage_summary":        {ScopeReadArtifacts},
+
+	// Annotation tools
+	"list_annotations":  {ScopeReadBuilds},
+	"create_annotation": {ScopeWriteBuilds},
+	"delete_annotation": {ScopeWriteBuilds},
+
+	// Test Run tools
+	"list_test_runs":        {ScopeReadSuites},
+	"get_test_run":          {ScopeReadSuites},
+	"get_test_run_build":    {ScopeReadSuites, ScopeReadBuilds},
+	"get_failed_executions": {ScopeReadSuites},
+	"find_flaky_tests":      {ScopeReadSuites},
+
+	// Test tools
+	"get_test":         {ScopeReadSuites},
+	"search_tests":     {ScopeReadSuites},
+	"get_test_history": {ScopeReadSuites},
+
+	// Test Suite tools
+	"list_test_suites": {ScopeReadSuites},
+
+	// Other tools
+	"access_token": {},
+}
+
+// RequiredScopes returns the token scopes a tool needs, and false if the tool's scopes aren't known
+func RequiredScopes(toolName string) ([]string, bool) {
+	scopes, ok := toolScopes[toolName]
+	return scopes, ok
+}
+
+// MissingScopes returns the scopes a tool needs which the token wasn't granted. Tools whose scopes
+// aren't known are assumed to be usable.
+func MissingScopes(toolName string, granted []string) []string {
+	var missing []string
+	for _, scope := range toolScopes[toolName] {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// WithScopesDescription adds the token scopes a tool needs to the end of its description
+func WithScopesDescription(tool mcp.Tool) mcp.Tool {
+	scopes := toolScopes[tool.Name]
+	switch len(scopes) {
+	case 0:
+		return tool
+	case 1:
+		tool.Description = fmt.Sprintf("%s. Requires the %s token scope", strings.TrimSuffix(tool.Description, "."), scopes[0])
+	default:
+		tool.Description = fmt.Sprintf("%s. Requires the %s and %s token scopes", strings.TrimSuffix(tool.Description, "."),
+			strings.Join(scopes[:len(scopes)-1], ", "), scopes[len(scopes)-1])
+	}
+	return tool
+}
@@ -0,0 +1,46 @@
+package buildkite
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingScopes(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Empty(MissingScopes("get_build", []string{ScopeReadBuilds, ScopeReadPipelines}))
+	assert.Equal([]string{ScopeReadBuildLogs}, MissingScopes("diagnose_build", []string{ScopeReadBuilds}))
+	assert.Equal([]string{ScopeReadClusters, ScopeWriteClusters}, MissingScopes("pause_queue_dispatch", nil))
+	assert.Empty(MissingScopes("lint_pipeline_yaml", nil))
+	assert.Empty(MissingScopes("unknown_tool", nil))
+}
+
+func TestRequiredScopes(t *testing.T) {
+	assert := require.New(t)
+
+	scopes, ok := RequiredScopes("get_job_logs")
+	assert.True(ok)
+	assert.Equal([]string{ScopeReadBuildLogs}, scopes)
+
+	scopes, ok = RequiredScopes("access_token")
+	assert.True(ok)
+	assert.Empty(scopes)
+
+	_, ok = RequiredScopes("unknown_tool")
+	assert.False(ok)
+}
+
+func TestWithScopesDescription(t *testing.T) {
+	assert := require.New(t)
+
+	tool := WithScopesDescription(mcp.NewTool("get_job_logs", mcp.WithDescription("Get the log output for a job")))
+	assert.Equal("Get the log output for a job. Requires the read_build_logs token scope", tool.Description)
+
+	tool = WithScopesDescription(mcp.NewTool("diagnose_build", mcp.WithDescription("Diagnose a failed build.")))
+	assert.Equal("Diagnose a failed build. Requires the read_builds and read_build_logs token scopes", tool.Description)
+
+	tool = WithScopesDescription(mcp.NewTool("lint_pipeline_yaml", mcp.WithDescription("Lint pipeline YAML")))
+	assert.Equal("Lint pipeline YAML", tool.Description)
+}
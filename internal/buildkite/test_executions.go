@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
@@ -20,7 +19,7 @@ type TestExecutionsClient interface {
 
 func GetFailedTestExecutions(ctx context.Context, client TestExecutionsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_failed_executions",
-			mcp.WithDescription("Get failed test executions for a specific test run in Buildkite Test Engine. Optionally get the expanded failure details such as full error messages and stack traces."),
+			mcp.WithDescription("Get failed test executions for a specific test run in Buildkite Test Engine. Optionally get the expanded failure details such as full error messages and stack traces, or group the failures by signature to see their distinct root causes."),
 			mcp.WithString("org",
 				mcp.Required(),
 				mcp.Description("The organization slug for the owner of the test suite"),
@@ -36,6 +35,10 @@ func GetFailedTestExecutions(ctx context.Context, client TestExecutionsClient) (
 			mcp.WithBoolean("include_failure_expanded",
 				mcp.Description("Include the expanded failure details such as full error messages and stack traces. This can be used to explain and diganose the cause of test failures."),
 			),
+			mcp.WithString("group_by",
+				mcp.Description("Set to 'signature' to cluster failures whose messages and stack traces match once line numbers, addresses and ids are stripped, returning each cluster's count, test names and one example execution, most common first. Implies include_failure_expanded"),
+				mcp.Enum("signature"),
+			),
 			withClientSidePagination(),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        "Get Failed Test Executions",
@@ -63,6 +66,15 @@ func GetFailedTestExecutions(ctx context.Context, client TestExecutionsClient) (
 
 			includeFailureExpanded := request.GetBool("include_failure_expanded", false)
 
+			groupBy := request.GetString("group_by", "")
+			switch groupBy {
+			case "":
+			case "signature":
+				includeFailureExpanded = true
+			default:
+				return mcp.NewToolResultError(fmt.Sprintf("invalid group_by %q, expected signature", groupBy)), nil
+			}
+
 			// Get client-side pagination parameters (always enabled)
 			paginationParams := getClientSidePaginationParams(request)
 
@@ -71,6 +83,7 @@ func GetFailedTestExecutions(ctx context.Context, client TestExecutionsClient) (
 				attribute.String("test_suite_slug", testSuiteSlug),
 				attribute.String("run_id", runID),
 				attribute.Bool("include_failure_expanded", includeFailureExpanded),
+				attribute.String("group_by", groupBy),
 				attribute.Int("page", paginationParams.Page),
 				attribute.Int("per_page", paginationParams.PerPage),
 			)
@@ -81,19 +94,23 @@ func GetFailedTestExecutions(ctx context.Context, client TestExecutionsClient) (
 
 			failedExecutions, resp, err := client.GetFailedExecutions(ctx, org, testSuiteSlug, runID, options)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get failed executions", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get failed executions: %s", string(body))), nil
+				return apiErrorResult("get failed executions", resp, nil), nil
+			}
+
+			var result any
+			if groupBy == "signature" {
+				groups := groupBySignature(failedExecutions)
+				span.SetAttributes(attribute.Int("signatures", len(groups)))
+				result = applyClientSidePagination(groups, paginationParams)
+			} else {
+				// Always apply client-side pagination
+				result = applyClientSidePagination(failedExecutions, paginationParams)
 			}
 
-			// Always apply client-side pagination
-			result := applyClientSidePagination(failedExecutions, paginationParams)
 			r, err := json.Marshal(&result)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal failed executions: %w", err)
@@ -2,6 +2,7 @@ package buildkite
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -63,7 +64,7 @@ func TestGetFailedExecutions(t *testing.T) {
 
 	// Test tool properties
 	assert.Equal("get_failed_executions", tool.Name)
-	assert.Equal("Get failed test executions for a specific test run in Buildkite Test Engine. Optionally get the expanded failure details such as full error messages and stack traces.", tool.Description)
+	assert.Equal("Get failed test executions for a specific test run in Buildkite Test Engine. Optionally get the expanded failure details such as full error messages and stack traces, or group the failures by signature to see their distinct root causes.", tool.Description)
 	assert.True(*tool.Annotations.ReadOnlyHint)
 
 	// Test successful request
@@ -415,4 +416,84 @@ func TestGetFailedExecutionsLargePage(t *testing.T) {
 	assert.Contains(textContentLargePage.Text, `"total":2`)
 	assert.Contains(textContentLargePage.Text, `"has_next":false`)
 	assert.Contains(textContentLargePage.Text, `"has_prev":false`)
-}
\ No newline at end of file
+}
+func TestGetFailedExecutionsGroupBySignature(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	fixtureFailure := func(id, name, record string, line int) buildkite.FailedExecution {
+		return buildkite.FailedExecution{
+			ExecutionID:   id,
+			TestName:      name,
+			FailureReason: fmt.Sprintf("ActiveRecord::RecordInvalid: record %s is invalid", record),
+			FailureExpanded: []buildkite.FailureExpanded{
+				{
+					Expanded:  []string{"Validation failed: Email has already been taken", ""},
+					Backtrace: []string{fmt.Sprintf("spec/factories/users.rb:%d:in `block'", line), "spec/support/fixtures.rb:12"},
+				},
+			},
+		}
+	}
+
+	failedExecutions := []buildkite.FailedExecution{
+		fixtureFailure("exec-1", "User signs up", "3f2b8c1e-9d4a-4b6e-8f0a-1c2d3e4f5a6b", 10),
+		{ExecutionID: "exec-2", TestName: "Billing renews", FailureReason: "Timeout after 30s waiting for 0x7ffe4a2c"},
+		fixtureFailure("exec-3", "User logs in", "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", 11),
+		fixtureFailure("exec-4", "User signs up", "0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f", 12),
+	}
+
+	var options *buildkite.FailedExecutionsOptions
+	mockClient := &MockTestExecutionsClient{
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			options = opt
+			return failedExecutions, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: http.StatusOK,
+				},
+			}, nil
+		},
+	}
+
+	_, handler := GetFailedTestExecutions(ctx, mockClient)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"run_id":          "run-123",
+		"group_by":        "signature",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.True(options.IncludeFailureExpanded)
+
+	var groups ClientSidePaginatedResult[FailureSignatureGroup]
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &groups))
+	assert.Equal(2, groups.Total)
+
+	assert.Equal(3, groups.Items[0].Count)
+	assert.Equal([]string{"User signs up", "User logs in"}, groups.Items[0].TestNames)
+	assert.Equal("exec-1", groups.Items[0].Example.ExecutionID)
+	assert.Equal("ActiveRecord::RecordInvalid: record <uuid> is invalid\nValidation failed: Email has already been taken\nspec/factories/users.rb:N:in `block'\nspec/support/fixtures.rb:N", groups.Items[0].Signature)
+
+	assert.Equal(1, groups.Items[1].Count)
+	assert.Equal("Timeout after Ns waiting for <addr>", groups.Items[1].Signature)
+}
+
+func TestGetFailedExecutionsInvalidGroupBy(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	_, handler := GetFailedTestExecutions(ctx, &MockTestExecutionsClient{})
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"run_id":          "run-123",
+		"group_by":        "test",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, `invalid group_by "test"`)
+}
@@ -0,0 +1,263 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/trace"
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Test execution results
+const (
+	TestResultFailed = "failed"
+	// TestResultNotFailed means the run finished without the test failing, which is either because it
+	// passed or because it wasn't run
+	TestResultNotFailed = "not_failed"
+)
+
+// defaultTestHistoryRuns is how many of a suite's most recent runs a test's history is read from by default
+const defaultTestHistoryRuns = 20
+
+// TestExecution is how a test did in one run. Durations are only reported for failed executions.
+type TestExecution struct {
+	RunID            string     `json:"run_id"`
+	Result           string     `json:"result"`
+	DurationSeconds  *float64   `json:"duration_seconds,omitempty"`
+	CommitSHA        string     `json:"commit_sha,omitempty"`
+	Branch           string     `json:"branch,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+	FailureReason    string     `json:"failure_reason,omitempty"`
+	RunURL           string     `json:"run_url,omitempty"`
+	TestExecutionURL string     `json:"test_execution_url,omitempty"`
+}
+
+// DurationTrend compares the mean duration of the older and newer halves of a test's executions
+type DurationTrend struct {
+	Samples             int     `json:"samples"`
+	PreviousMeanSeconds float64 `json:"previous_mean_seconds"`
+	RecentMeanSeconds   float64 `json:"recent_mean_seconds"`
+	ChangePercent       float64 `json:"change_percent"`
+}
+
+// TestHistory is a test's executions across a suite's recent runs, newest first. FailingSince is the
+// oldest execution of the streak of failures the test is currently on, if it's failing. Truncated is
+// set when a branch's runs were scanned for and older runs were left unread.
+type TestHistory struct {
+	Test               buildkite.Test  `json:"test"`
+	RunsAnalyzed       int             `json:"runs_analyzed"`
+	RunsSkipped        int             `json:"runs_skipped,omitempty"`
+	Truncated          bool            `json:"truncated,omitempty"`
+	Failures           int             `json:"failures"`
+	FailureRatePercent float64         `json:"failure_rate_percent"`
+	FailingSince       *TestExecution  `json:"failing_since,omitempty"`
+	DurationTrend      *DurationTrend  `json:"duration_trend,omitempty"`
+	Executions         []TestExecution `json:"executions"`
+	Errors             []string        `json:"errors,omitempty"`
+}
+
+func GetTestHistory(ctx context.Context, tests TestsClient, testRuns TestRunsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_test_history",
+			mcp.WithDescription("Get the recent history of a single test in Buildkite Test Engine: how it did in each of the suite's most recent finished runs (result, duration, commit and branch), its failure rate, the commit it started failing on if it's currently failing, and whether its duration is trending up. The API only reports executions which failed, so runs the test didn't fail in are reported as not_failed, and durations come from failed executions"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the test suite"),
+			),
+			mcp.WithString("test_suite_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the test suite"),
+			),
+			mcp.WithString("test_id",
+				mcp.Required(),
+				mcp.Description("The ID of the test"),
+			),
+			mcp.WithString("branch",
+				mcp.Description("Only include runs of this branch"),
+			),
+			mcp.WithNumber("runs",
+				mcp.Description("Number of the most recent runs to read the test's history from (default 20)"),
+				mcp.Min(1),
+				mcp.Max(maxFlakyTestRuns),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get Test History",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetTestHistory")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			testSuiteSlug, err := request.RequireString("test_suite_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			testID, err := request.RequireString("test_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			branch := request.GetString("branch", "")
+
+			runs := request.GetInt("runs", defaultTestHistoryRuns)
+			if runs < 1 || runs > maxFlakyTestRuns {
+				return mcp.NewToolResultError(fmt.Sprintf("runs must be between 1 and %d", maxFlakyTestRuns)), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("test_suite_slug", testSuiteSlug),
+				attribute.String("test_id", testID),
+				attribute.String("branch", branch),
+				attribute.Int("runs", runs),
+			)
+
+			test, resp, err := tests.Get(ctx, org, testSuiteSlug, testID)
+			if err != nil {
+				return apiErrorResult("get test", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get test", resp, nil), nil
+			}
+
+			var (
+				recent    []buildkite.TestRun
+				truncated bool
+			)
+			if branch == "" {
+				recent, err = listTestRunsUpTo(ctx, testRuns, org, testSuiteSlug, runs)
+			} else {
+				recent, truncated, err = scanTestRuns(ctx, testRuns, org, testSuiteSlug, testRunFilter{branch: branch})
+			}
+			if err != nil {
+				return toolErrorResult(err), nil
+			}
+
+			// runs which are still going may not have reported all of their failures yet
+			finished := make([]buildkite.TestRun, 0, len(recent))
+			for _, run := range recent {
+				if (run.State == "" || run.State == "finished") && (branch == "" || run.Branch == branch) {
+					finished = append(finished, run)
+				}
+			}
+			if len(finished) > runs {
+				finished = finished[:runs]
+				truncated = false
+			}
+
+			analyzed, failures, errs := getFailedExecutions(ctx, testRuns, org, testSuiteSlug, finished)
+
+			history := testHistory(testID, analyzed, failures)
+			history.Test = test
+			history.RunsSkipped = len(finished) - len(analyzed)
+			history.Truncated = truncated
+			history.Errors = errs
+
+			span.SetAttributes(attribute.Int("failures", history.Failures))
+
+			r, err := json.Marshal(&history)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal test history: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// testHistory picks out a test's executions from the failed executions of runs ordered newest first
+func testHistory(testID string, runs []buildkite.TestRun, failures [][]buildkite.FailedExecution) TestHistory {
+	history := TestHistory{
+		RunsAnalyzed: len(runs),
+		Executions:   make([]TestExecution, 0, len(runs)),
+	}
+
+	for i, run := range runs {
+		execution := TestExecution{
+			RunID:     run.ID,
+			Result:    TestResultNotFailed,
+			CommitSHA: run.CommitSHA,
+			Branch:    run.Branch,
+			RunURL:    run.WebURL,
+		}
+		if run.CreatedAt != nil {
+			execution.CreatedAt = &run.CreatedAt.Time
+		}
+
+		for _, failed := range failures[i] {
+			if failed.TestID != testID {
+				continue
+			}
+
+			execution.Result = TestResultFailed
+			execution.FailureReason = failed.FailureReason
+			execution.TestExecutionURL = failed.TestExecutionURL
+			if failed.Duration > 0 {
+				duration := round(failed.Duration, 3)
+				execution.DurationSeconds = &duration
+			}
+			if failed.CommitSHA != "" {
+				execution.CommitSHA = failed.CommitSHA
+			}
+			if failed.Branch != "" {
+				execution.Branch = failed.Branch
+			}
+			if failed.RunURL != "" {
+				execution.RunURL = failed.RunURL
+			}
+			break
+		}
+
+		history.Executions = append(history.Executions, execution)
+	}
+
+	for i, execution := range history.Executions {
+		if execution.Result != TestResultFailed {
+			continue
+		}
+		history.Failures++
+		if i == history.Failures-1 {
+			history.FailingSince = &history.Executions[i]
+		}
+	}
+	history.FailureRatePercent = percent(history.Failures, len(runs))
+
+	history.DurationTrend = durationTrend(history.Executions)
+
+	return history
+}
+
+// durationTrend needs at least two executions with a duration, ordered newest first
+func durationTrend(executions []TestExecution) *DurationTrend {
+	var durations []float64
+	for i := len(executions) - 1; i >= 0; i-- {
+		if executions[i].DurationSeconds != nil {
+			durations = append(durations, *executions[i].DurationSeconds)
+		}
+	}
+	if len(durations) < 2 {
+		return nil
+	}
+
+	half := len(durations) / 2
+	trend := &DurationTrend{
+		Samples:             len(durations),
+		PreviousMeanSeconds: round(mean(durations[:half]), 3),
+		RecentMeanSeconds:   round(mean(durations[half:]), 3),
+	}
+	if trend.PreviousMeanSeconds > 0 {
+		trend.ChangePercent = round((trend.RecentMeanSeconds-trend.PreviousMeanSeconds)*100/trend.PreviousMeanSeconds, 1)
+	}
+	return trend
+}
@@ -0,0 +1,102 @@
+package buildkite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTestHistory(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	day := func(n int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: time.Date(2025, 6, n, 0, 0, 0, 0, time.UTC)}
+	}
+
+	runs := []buildkite.TestRun{
+		{ID: "run6", CommitSHA: "fff", Branch: "main", State: "running", CreatedAt: day(6)},
+		{ID: "run5", CommitSHA: "eee", Branch: "main", State: "finished", Result: "failed", CreatedAt: day(5)},
+		{ID: "run4", CommitSHA: "ddd", Branch: "feature", State: "finished", Result: "failed", CreatedAt: day(4)},
+		{ID: "run3", CommitSHA: "ccc", Branch: "main", State: "finished", Result: "failed", CreatedAt: day(3)},
+		{ID: "run2", CommitSHA: "bbb", Branch: "main", State: "finished", Result: "passed", CreatedAt: day(2)},
+		{ID: "run1", CommitSHA: "aaa", Branch: "main", State: "finished", Result: "failed", CreatedAt: day(1)},
+	}
+
+	failures := map[string][]buildkite.FailedExecution{
+		"run5": {{TestID: "test-1", Duration: 4, FailureReason: "timeout"}},
+		"run4": {{TestID: "test-2", Duration: 1}},
+		"run3": {{TestID: "test-1", Duration: 3, FailureReason: "timeout"}},
+		"run1": {{TestID: "test-1", Duration: 1}, {TestID: "test-2", Duration: 1}},
+	}
+
+	tests := &MockTestsClient{
+		GetFunc: func(ctx context.Context, org, slug, testID string) (buildkite.Test, *buildkite.Response, error) {
+			return buildkite.Test{ID: testID, Name: "it works"}, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+	}
+
+	testRuns := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			return runs, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			return failures[runID], &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := GetTestHistory(ctx, tests, testRuns)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+	assert.Equal("get_test_history", tool.Name)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"test_id":         "test-1",
+		"branch":          "main",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var history TestHistory
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &history))
+	assert.Equal("it works", history.Test.Name)
+	assert.Equal(4, history.RunsAnalyzed)
+	assert.Equal(3, history.Failures)
+	assert.Equal(75.0, history.FailureRatePercent)
+
+	results := make([]string, 0, len(history.Executions))
+	for _, execution := range history.Executions {
+		results = append(results, execution.RunID+":"+execution.Result)
+	}
+	assert.Equal([]string{"run5:failed", "run3:failed", "run2:not_failed", "run1:failed"}, results)
+	assert.Nil(history.Executions[2].DurationSeconds)
+
+	assert.NotNil(history.FailingSince)
+	assert.Equal("ccc", history.FailingSince.CommitSHA)
+
+	assert.NotNil(history.DurationTrend)
+	assert.Equal(3, history.DurationTrend.Samples)
+	assert.Equal(1.0, history.DurationTrend.PreviousMeanSeconds)
+	assert.Equal(3.5, history.DurationTrend.RecentMeanSeconds)
+	assert.Equal(250.0, history.DurationTrend.ChangePercent)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -20,9 +23,54 @@ type TestRunsClient interface {
 	GetFailedExecutions(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error)
 }
 
-func ListTestRuns(ctx context.Context, client TestRunsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+// maxFilteredTestRuns caps how many of a suite's most recent runs are scanned when filtering runs
+const maxFilteredTestRuns = 1000
+
+// testRunFilter narrows a suite's runs down, as the API only lists them newest first
+type testRunFilter struct {
+	commitSHA   string
+	branch      string
+	createdFrom time.Time
+	createdTo   time.Time
+}
+
+func (f testRunFilter) empty() bool {
+	return f == testRunFilter{}
+}
+
+func (f testRunFilter) matches(run buildkite.TestRun) bool {
+	if f.commitSHA != "" && !strings.HasPrefix(run.CommitSHA, f.commitSHA) {
+		return false
+	}
+	if f.branch != "" && run.Branch != f.branch {
+		return false
+	}
+	if run.CreatedAt != nil {
+		if !f.createdFrom.IsZero() && run.CreatedAt.Before(f.createdFrom) {
+			return false
+		}
+		if !f.createdTo.IsZero() && !run.CreatedAt.Before(f.createdTo) {
+			return false
+		}
+	}
+	return true
+}
+
+// exhausted reports whether a run is older than the filter's window, so no later page can match
+func (f testRunFilter) exhausted(run buildkite.TestRun) bool {
+	return !f.createdFrom.IsZero() && run.CreatedAt != nil && run.CreatedAt.Before(f.createdFrom)
+}
+
+// TestRunList is a page of the runs matching a filter. Truncated is set when the scan stopped at
+// maxFilteredTestRuns with older runs left, so runs which would match may be missing.
+type TestRunList struct {
+	ClientSidePaginatedResult[buildkite.TestRun]
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func ListTestRuns(ctx context.Context, client TestRunsClient, builds BuildsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_test_runs",
-			mcp.WithDescription("List all test runs for a test suite in Buildkite Test Engine"),
+			mcp.WithDescription("List all test runs for a test suite in Buildkite Test Engine. Runs can be filtered by commit, branch, creation time, or the Pipelines build they came from (pipeline_slug and build_number together); filtering scans up to the 1000 most recent runs, marking the result truncated when older runs are left, and paginates the matches client-side"),
 			mcp.WithString("org",
 				mcp.Required(),
 				mcp.Description("The organization slug for the owner of the test suite"),
@@ -31,6 +79,24 @@ func ListTestRuns(ctx context.Context, client TestRunsClient) (tool mcp.Tool, ha
 				mcp.Required(),
 				mcp.Description("The slug of the test suite"),
 			),
+			mcp.WithString("commit_sha",
+				mcp.Description("Only include runs of this commit, or of commits starting with this prefix"),
+			),
+			mcp.WithString("branch",
+				mcp.Description("Only include runs of this branch"),
+			),
+			mcp.WithString("created_from",
+				mcp.Description("Only include runs created at or after this time, in RFC 3339 format e.g. 2025-06-01T00:00:00Z"),
+			),
+			mcp.WithString("created_to",
+				mcp.Description("Only include runs created before this time, in RFC 3339 format"),
+			),
+			mcp.WithString("pipeline_slug",
+				mcp.Description("Only include runs from a build of this pipeline, given with build_number"),
+			),
+			mcp.WithString("build_number",
+				mcp.Description("Only include runs from this build of pipeline_slug"),
+			),
 			withPagination(),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        "List Test Runs",
@@ -56,28 +122,78 @@ func ListTestRuns(ctx context.Context, client TestRunsClient) (tool mcp.Tool, ha
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			filter := testRunFilter{
+				commitSHA: request.GetString("commit_sha", ""),
+				branch:    request.GetString("branch", ""),
+			}
+			for name, t := range map[string]*time.Time{"created_from": &filter.createdFrom, "created_to": &filter.createdTo} {
+				if value := request.GetString(name, ""); value != "" {
+					if *t, err = time.Parse(time.RFC3339, value); err != nil {
+						return mcp.NewToolResultError(fmt.Sprintf("invalid %s: %s", name, err)), nil
+					}
+				}
+			}
+
+			pipelineSlug := request.GetString("pipeline_slug", "")
+			buildNumber := request.GetString("build_number", "")
+			if (pipelineSlug == "") != (buildNumber == "") {
+				return mcp.NewToolResultError("pipeline_slug and build_number must be given together"), nil
+			}
+
 			span.SetAttributes(
 				attribute.String("org", org),
 				attribute.String("test_suite_slug", testSuiteSlug),
 				attribute.Int("page", paginationParams.Page),
 				attribute.Int("per_page", paginationParams.PerPage),
+				attribute.String("commit_sha", filter.commitSHA),
+				attribute.String("branch", filter.branch),
+				attribute.String("pipeline_slug", pipelineSlug),
+				attribute.String("build_number", buildNumber),
 			)
 
+			if pipelineSlug != "" || !filter.empty() {
+				var (
+					testRuns  []buildkite.TestRun
+					truncated bool
+				)
+				if pipelineSlug != "" {
+					testRuns, err = getBuildTestRuns(ctx, client, builds, org, testSuiteSlug, pipelineSlug, buildNumber)
+				} else {
+					testRuns, truncated, err = scanTestRuns(ctx, client, org, testSuiteSlug, filter)
+				}
+				if err != nil {
+					return toolErrorResult(err), nil
+				}
+
+				matched := make([]buildkite.TestRun, 0, len(testRuns))
+				for _, run := range testRuns {
+					if filter.matches(run) {
+						matched = append(matched, run)
+					}
+				}
+
+				result := TestRunList{
+					ClientSidePaginatedResult: applyClientSidePagination(matched, getClientSidePaginationParams(request)),
+					Truncated:                 truncated,
+				}
+				r, err := json.Marshal(&result)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal test runs: %w", err)
+				}
+				return mcp.NewToolResultText(string(r)), nil
+			}
+
 			options := &buildkite.TestRunsListOptions{
 				ListOptions: paginationParams,
 			}
 
 			testRuns, resp, err := client.List(ctx, org, testSuiteSlug, options)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("list test runs", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get test runs: %s", string(body))), nil
+				return apiErrorResult("list test runs", resp, nil), nil
 			}
 
 			result := PaginatedResult[buildkite.TestRun]{
@@ -96,6 +212,79 @@ func ListTestRuns(ctx context.Context, client TestRunsClient) (tool mcp.Tool, ha
 		}
 }
 
+// scanTestRuns reads a suite's runs newest first until they're older than the filter's window, up to
+// maxFilteredTestRuns. It reports whether runs in the window were left unread.
+func scanTestRuns(ctx context.Context, client TestRunsClient, org, testSuiteSlug string, filter testRunFilter) ([]buildkite.TestRun, bool, error) {
+	var all []buildkite.TestRun
+
+	options := &buildkite.TestRunsListOptions{
+		ListOptions: buildkite.ListOptions{PerPage: 100},
+	}
+	for {
+		testRuns, resp, err := client.List(ctx, org, testSuiteSlug, options)
+		if err != nil {
+			return nil, false, newAPIError("list test runs", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, false, newAPIError("list test runs", resp, nil)
+		}
+
+		for _, run := range testRuns {
+			if filter.exhausted(run) {
+				return all, false, nil
+			}
+			all = append(all, run)
+		}
+
+		if resp.NextPage == 0 {
+			return all, false, nil
+		}
+		if len(all) >= maxFilteredTestRuns {
+			return all, true, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+// getBuildTestRuns fetches the runs of a suite which a Pipelines build reported to Test Engine
+func getBuildTestRuns(ctx context.Context, client TestRunsClient, builds BuildsClient, org, testSuiteSlug, pipelineSlug, buildNumber string) ([]buildkite.TestRun, error) {
+	build, resp, err := builds.Get(ctx, org, pipelineSlug, buildNumber, &buildkite.BuildGetOptions{
+		IncludeTestEngine: true,
+	})
+	if err != nil {
+		return nil, newAPIError("get build", resp, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("get build", resp, nil)
+	}
+
+	var testRuns []buildkite.TestRun
+	if build.TestEngine == nil {
+		return testRuns, nil
+	}
+
+	for _, engineRun := range build.TestEngine.Runs {
+		if engineRun.Suite.Slug != testSuiteSlug {
+			continue
+		}
+
+		run, resp, err := client.Get(ctx, org, testSuiteSlug, engineRun.ID)
+		if err != nil {
+			return nil, newAPIError("get test run", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, newAPIError("get test run", resp, nil)
+		}
+
+		testRuns = append(testRuns, run)
+	}
+
+	return testRuns, nil
+}
+
 func GetTestRun(ctx context.Context, client TestRunsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_test_run",
 			mcp.WithDescription("Get a specific test run in Buildkite Test Engine"),
@@ -143,15 +332,11 @@ func GetTestRun(ctx context.Context, client TestRunsClient) (tool mcp.Tool, hand
 
 			testRun, resp, err := client.Get(ctx, org, testSuiteSlug, runID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get test run", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get test run: %s", string(body))), nil
+				return apiErrorResult("get test run", resp, nil), nil
 			}
 
 			r, err := json.Marshal(&testRun)
@@ -163,4 +348,179 @@ func GetTestRun(ctx context.Context, client TestRunsClient) (tool mcp.Tool, hand
 		}
 }
 
+// maxRunBuildCandidates caps how many builds of a run's commit are checked for the run
+const maxRunBuildCandidates = 10
 
+// BuildReference identifies a Pipelines build
+type BuildReference struct {
+	PipelineSlug string               `json:"pipeline_slug"`
+	Number       int                  `json:"number"`
+	State        string               `json:"state"`
+	Branch       string               `json:"branch"`
+	Commit       string               `json:"commit"`
+	WebURL       string               `json:"web_url"`
+	CreatedAt    *buildkite.Timestamp `json:"created_at,omitempty"`
+}
+
+// TestRunBuild links a Test Engine run to the Pipelines build which reported it. When no build could
+// be confirmed, the builds of the same commit are listed as candidates, and Truncated is set if the
+// commit had more builds than were checked.
+type TestRunBuild struct {
+	Run        buildkite.TestRun `json:"run"`
+	Build      *BuildReference   `json:"build,omitempty"`
+	Candidates []BuildReference  `json:"candidates,omitempty"`
+	Truncated  bool              `json:"truncated,omitempty"`
+}
+
+func GetTestRunBuild(ctx context.Context, client TestRunsClient, builds BuildsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_test_run_build",
+			mcp.WithDescription("Find the Buildkite Pipelines build a Test Engine run came from, by looking through the organization's builds of the run's commit for the one which reported the run. If none can be confirmed the builds of the same commit are returned as candidates"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the test suite"),
+			),
+			mcp.WithString("test_suite_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the test suite"),
+			),
+			mcp.WithString("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the test run"),
+			),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get Test Run Build",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.GetTestRunBuild")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			testSuiteSlug, err := request.RequireString("test_suite_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			runID, err := request.RequireString("run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("test_suite_slug", testSuiteSlug),
+				attribute.String("run_id", runID),
+			)
+
+			run, resp, err := client.Get(ctx, org, testSuiteSlug, runID)
+			if err != nil {
+				return apiErrorResult("get test run", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get test run", resp, nil), nil
+			}
+
+			if run.CommitSHA == "" {
+				return mcp.NewToolResultError("test run has no commit to find its build by"), nil
+			}
+
+			options := &buildkite.BuildsListOptions{
+				Commit:      run.CommitSHA,
+				ExcludeJobs: true,
+				ListOptions: buildkite.ListOptions{PerPage: maxRunBuildCandidates},
+			}
+			if run.Branch != "" {
+				options.Branch = []string{run.Branch}
+			}
+
+			candidates, resp, err := builds.ListByOrg(ctx, org, options)
+			if err != nil {
+				return apiErrorResult("list builds", resp, err), nil
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("list builds", resp, nil), nil
+			}
+
+			result := TestRunBuild{Run: run}
+			for _, candidate := range candidates {
+				if candidate.Pipeline == nil {
+					continue
+				}
+
+				reference := BuildReference{
+					PipelineSlug: candidate.Pipeline.Slug,
+					Number:       candidate.Number,
+					State:        candidate.State,
+					Branch:       candidate.Branch,
+					Commit:       candidate.Commit,
+					WebURL:       candidate.WebURL,
+					CreatedAt:    candidate.CreatedAt,
+				}
+
+				build, resp, err := builds.Get(ctx, org, reference.PipelineSlug, strconv.Itoa(reference.Number), &buildkite.BuildGetOptions{
+					IncludeTestEngine: true,
+				})
+				if err != nil || resp.StatusCode != http.StatusOK {
+					result.Candidates = append(result.Candidates, reference)
+					continue
+				}
+
+				if build.TestEngine != nil && slices.ContainsFunc(build.TestEngine.Runs, func(r buildkite.TestEngineRun) bool {
+					return r.ID == run.ID
+				}) {
+					result.Build = &reference
+					result.Candidates = nil
+					break
+				}
+				result.Candidates = append(result.Candidates, reference)
+			}
+
+			result.Truncated = result.Build == nil && resp.NextPage != 0
+
+			span.SetAttributes(attribute.Bool("found", result.Build != nil))
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal test run build: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// listTestRunsUpTo follows the pages of a suite's test runs until there are no more or limit runs have
+// been read
+func listTestRunsUpTo(ctx context.Context, client TestRunsClient, org, testSuiteSlug string, limit int) ([]buildkite.TestRun, error) {
+	var all []buildkite.TestRun
+
+	options := &buildkite.TestRunsListOptions{
+		ListOptions: buildkite.ListOptions{PerPage: min(100, limit)},
+	}
+	for {
+		testRuns, resp, err := client.List(ctx, org, testSuiteSlug, options)
+		if err != nil {
+			return nil, newAPIError("list test runs", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, newAPIError("list test runs", resp, nil)
+		}
+
+		all = append(all, testRuns...)
+		if len(all) >= limit {
+			return all[:limit], nil
+		}
+
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
@@ -2,11 +2,13 @@ package buildkite
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buildkite/go-buildkite/v4"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -74,11 +76,11 @@ func TestListTestRuns(t *testing.T) {
 		},
 	}
 
-	tool, handler := ListTestRuns(ctx, mockClient)
+	tool, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
 
 	// Test tool properties
 	assert.Equal("list_test_runs", tool.Name)
-	assert.Equal("List all test runs for a test suite in Buildkite Test Engine", tool.Description)
+	assert.Equal("List all test runs for a test suite in Buildkite Test Engine. Runs can be filtered by commit, branch, creation time, or the Pipelines build they came from (pipeline_slug and build_number together); filtering scans up to the 1000 most recent runs, marking the result truncated when older runs are left, and paginates the matches client-side", tool.Description)
 	assert.True(*tool.Annotations.ReadOnlyHint)
 
 	// Test successful request
@@ -112,7 +114,7 @@ func TestListTestRunsWithError(t *testing.T) {
 		},
 	}
 
-	_, handler := ListTestRuns(ctx, mockClient)
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
 
 	request := createMCPRequest(t, map[string]any{
 		"org":             "org",
@@ -131,7 +133,7 @@ func TestListTestRunsMissingOrg(t *testing.T) {
 	ctx := context.Background()
 	mockClient := &MockTestRunsClient{}
 
-	_, handler := ListTestRuns(ctx, mockClient)
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
 
 	request := createMCPRequest(t, map[string]any{
 		"test_suite_slug": "suite1",
@@ -149,7 +151,7 @@ func TestListTestRunsMissingTestSuiteSlug(t *testing.T) {
 	ctx := context.Background()
 	mockClient := &MockTestRunsClient{}
 
-	_, handler := ListTestRuns(ctx, mockClient)
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
 
 	request := createMCPRequest(t, map[string]any{
 		"org": "org",
@@ -333,7 +335,7 @@ func TestListTestRunsHTTPError(t *testing.T) {
 		},
 	}
 
-	_, handler := ListTestRuns(ctx, mockClient)
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
 
 	request := createMCPRequest(t, map[string]any{
 		"org":             "org",
@@ -346,3 +348,249 @@ func TestListTestRunsHTTPError(t *testing.T) {
 	assert.Contains(result.Content[0].(mcp.TextContent).Text, "Access denied")
 }
 
+
+func TestListTestRunsFilters(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	day := func(n int) *buildkite.Timestamp {
+		return &buildkite.Timestamp{Time: time.Date(2025, 6, n, 12, 0, 0, 0, time.UTC)}
+	}
+
+	var pagesRead []int
+	mockClient := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			pagesRead = append(pagesRead, opt.Page)
+			runs := []buildkite.TestRun{
+				{ID: "run4", Branch: "main", CommitSHA: "abc123", CreatedAt: day(4)},
+				{ID: "run3", Branch: "feature", CommitSHA: "abc123", CreatedAt: day(3)},
+			}
+			nextPage := 2
+			if opt.Page == 2 {
+				runs = []buildkite.TestRun{
+					{ID: "run2", Branch: "main", CommitSHA: "def456", CreatedAt: day(2)},
+					{ID: "run1", Branch: "main", CommitSHA: "abc123", CreatedAt: day(1)},
+				}
+				nextPage = 3
+			}
+			return runs, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: nextPage,
+				}, nil
+		},
+	}
+
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"commit_sha":      "abc",
+		"branch":          "main",
+		"created_from":    "2025-06-02T00:00:00Z",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var runs TestRunList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &runs))
+	assert.Equal(1, runs.Total)
+	assert.Equal("run4", runs.Items[0].ID)
+	// run1 is older than created_from, so the third page is never read
+	assert.Equal([]int{0, 2}, pagesRead)
+	assert.False(runs.Truncated)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"created_from":    "yesterday",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "invalid created_from")
+}
+
+func TestListTestRunsFiltersTruncated(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	var pages int
+	mockClient := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			pages++
+			runs := make([]buildkite.TestRun, opt.PerPage)
+			for i := range runs {
+				runs[i] = buildkite.TestRun{ID: fmt.Sprintf("run%d-%d", pages, i), Branch: "feature"}
+			}
+			return runs, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: pages + 1,
+				}, nil
+		},
+	}
+
+	_, handler := ListTestRuns(ctx, mockClient, &MockBuildsClient{})
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"branch":          "main",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	// no runs of main among the most recent ones isn't the same as main having no runs
+	var runs TestRunList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &runs))
+	assert.Equal(0, runs.Total)
+	assert.True(runs.Truncated)
+	assert.Equal(maxFilteredTestRuns/100, pages)
+}
+
+func TestListTestRunsByBuild(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	builds := &MockBuildsClient{
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			assert.Equal("pipeline", pipeline)
+			assert.Equal("42", id)
+			assert.True(opt.IncludeTestEngine)
+			return buildkite.Build{
+					TestEngine: &buildkite.TestEngineProperty{
+						Runs: []buildkite.TestEngineRun{
+							{ID: "run1", Suite: buildkite.TestEngineSuite{Slug: "suite"}},
+							{ID: "run2", Suite: buildkite.TestEngineSuite{Slug: "other-suite"}},
+						},
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	mockClient := &MockTestRunsClient{
+		GetFunc: func(ctx context.Context, org, slug, runID string) (buildkite.TestRun, *buildkite.Response, error) {
+			return buildkite.TestRun{ID: runID, Branch: "main"}, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+	}
+
+	_, handler := ListTestRuns(ctx, mockClient, builds)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"pipeline_slug":   "pipeline",
+		"build_number":    "42",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var runs TestRunList
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &runs))
+	assert.Equal(1, runs.Total)
+	assert.Equal("run1", runs.Items[0].ID)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"build_number":    "42",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.True(result.IsError)
+	assert.Contains(getTextResult(t, result).Text, "must be given together")
+}
+
+func TestGetTestRunBuild(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	mockClient := &MockTestRunsClient{
+		GetFunc: func(ctx context.Context, org, slug, runID string) (buildkite.TestRun, *buildkite.Response, error) {
+			return buildkite.TestRun{ID: runID, Branch: "main", CommitSHA: "abc123"}, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+	}
+
+	builds := &MockBuildsClient{
+		ListByOrgFunc: func(ctx context.Context, org string, opt *buildkite.BuildsListOptions) ([]buildkite.Build, *buildkite.Response, error) {
+			assert.Equal("abc123", opt.Commit)
+			assert.Equal([]string{"main"}, opt.Branch)
+			return []buildkite.Build{
+					{Number: 7, Commit: "abc123", Branch: "main", Pipeline: &buildkite.Pipeline{Slug: "lint"}},
+					{Number: 12, Commit: "abc123", Branch: "main", State: "failed", Pipeline: &buildkite.Pipeline{Slug: "app"}},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+					NextPage: 2,
+				}, nil
+		},
+		GetFunc: func(ctx context.Context, org string, pipeline string, id string, opt *buildkite.BuildGetOptions) (buildkite.Build, *buildkite.Response, error) {
+			build := buildkite.Build{TestEngine: &buildkite.TestEngineProperty{}}
+			if pipeline == "app" {
+				build.TestEngine.Runs = []buildkite.TestEngineRun{{ID: "run1"}}
+			}
+			return build, &buildkite.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+				},
+			}, nil
+		},
+	}
+
+	tool, handler := GetTestRunBuild(ctx, mockClient, builds)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"run_id":          "run1",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var runBuild TestRunBuild
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &runBuild))
+	assert.Equal("run1", runBuild.Run.ID)
+	assert.NotNil(runBuild.Build)
+	assert.Equal("app", runBuild.Build.PipelineSlug)
+	assert.Equal(12, runBuild.Build.Number)
+	assert.Empty(runBuild.Candidates)
+	assert.False(runBuild.Truncated)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"run_id":          "run9",
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+
+	runBuild = TestRunBuild{}
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &runBuild))
+	assert.Nil(runBuild.Build)
+	assert.Len(runBuild.Candidates, 2)
+	// the commit has more builds than were checked, so the run's build may be among them
+	assert.True(runBuild.Truncated)
+}
@@ -0,0 +1,61 @@
+package buildkite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/buildkite/go-buildkite/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTestSuitesClient struct {
+	ListFunc func(ctx context.Context, org string, opt *buildkite.TestSuiteListOptions) ([]buildkite.TestSuite, *buildkite.Response, error)
+}
+
+func (m *MockTestSuitesClient) List(ctx context.Context, org string, opt *buildkite.TestSuiteListOptions) ([]buildkite.TestSuite, *buildkite.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, org, opt)
+	}
+	return nil, nil, nil
+}
+
+var _ TestSuitesClient = (*MockTestSuitesClient)(nil)
+
+func TestListTestSuites(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &MockTestSuitesClient{
+		ListFunc: func(ctx context.Context, org string, opt *buildkite.TestSuiteListOptions) ([]buildkite.TestSuite, *buildkite.Response, error) {
+			assert.Equal("org", org)
+			assert.Equal(2, opt.Page)
+			return []buildkite.TestSuite{
+					{
+						ID:            "suite-123",
+						Slug:          "rspec",
+						Name:          "RSpec",
+						DefaultBranch: "main",
+					},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := ListTestSuites(ctx, client)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":  "org",
+		"page": float64(2),
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	textContent := getTextResult(t, result)
+	assert.JSONEq(`{"headers":{"Link":""},"items":[{"id":"suite-123","slug":"rspec","name":"RSpec","default_branch":"main"}]}`, textContent.Text)
+}
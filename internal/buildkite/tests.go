@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -18,6 +20,35 @@ type TestsClient interface {
 	Get(ctx context.Context, org, slug, testID string) (buildkite.Test, *buildkite.Response, error)
 }
 
+type FlakyTestsClient interface {
+	List(ctx context.Context, org, slug string, opt *buildkite.FlakyTestsListOptions) ([]buildkite.FlakyTest, *buildkite.Response, error)
+}
+
+const (
+	// defaultSearchTestRuns is how many recent runs are scanned for failed executions when searching tests
+	defaultSearchTestRuns = 20
+	// maxFlakyTestPages caps how many pages of a suite's flaky tests are read when searching tests
+	maxFlakyTestPages = 10
+)
+
+// TestMatch is a test found by search_tests, along with where it was seen
+type TestMatch struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Scope    string     `json:"scope,omitempty"`
+	Location string     `json:"location,omitempty"`
+	FileName string     `json:"file_name,omitempty"`
+	WebURL   string     `json:"web_url,omitempty"`
+	Sources  []string   `json:"sources"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+}
+
+// TestSearchResult are the tests matching a search
+type TestSearchResult struct {
+	Tests  ClientSidePaginatedResult[TestMatch] `json:"tests"`
+	Errors []string                             `json:"errors,omitempty"`
+}
+
 func GetTest(ctx context.Context, client TestsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_test",
 			mcp.WithDescription("Get a specific test in Buildkite Test Engine. This provides additional metadata for failed test executions"),
@@ -65,15 +96,11 @@ func GetTest(ctx context.Context, client TestsClient) (tool mcp.Tool, handler se
 
 			test, resp, err := client.Get(ctx, org, testSuiteSlug, testID)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get test", resp, err), nil
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					return nil, fmt.Errorf("failed to read response body: %w", err)
-				}
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get test: %s", string(body))), nil
+				return apiErrorResult("get test", resp, nil), nil
 			}
 
 			r, err := json.Marshal(&test)
@@ -84,3 +111,209 @@ func GetTest(ctx context.Context, client TestsClient) (tool mcp.Tool, handler se
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+func SearchTests(ctx context.Context, flakyTests FlakyTestsClient, testRuns TestRunsClient) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("search_tests",
+			mcp.WithDescription("Search for tests in a Buildkite Test Engine suite by a case-insensitive substring of their name, scope or file path, to find test IDs for get_test and related tools. The API has no index of every test, so this searches the suite's flaky tests and the failed executions of its most recent runs; tests which have always passed won't be found"),
+			mcp.WithString("org",
+				mcp.Required(),
+				mcp.Description("The organization slug for the owner of the test suite"),
+			),
+			mcp.WithString("test_suite_slug",
+				mcp.Required(),
+				mcp.Description("The slug of the test suite"),
+			),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("Text to look for, matched case-insensitively"),
+			),
+			mcp.WithString("field",
+				mcp.Description("Which field to match the query against (default any)"),
+				mcp.Enum("any", "name", "scope", "location"),
+			),
+			mcp.WithNumber("runs",
+				mcp.Description("Number of the most recent runs to search the failed executions of (default 20)"),
+				mcp.Min(0),
+				mcp.Max(maxFlakyTestRuns),
+			),
+			withClientSidePagination(),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Search Tests",
+				ReadOnlyHint: mcp.ToBoolPtr(true),
+			}),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := trace.Start(ctx, "buildkite.SearchTests")
+			defer span.End()
+
+			org, err := request.RequireString("org")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			testSuiteSlug, err := request.RequireString("test_suite_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			query, err := request.RequireString("query")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			field := request.GetString("field", "any")
+			if !slices.Contains([]string{"any", "name", "scope", "location"}, field) {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid field %q, expected any, name, scope or location", field)), nil
+			}
+
+			runs := request.GetInt("runs", defaultSearchTestRuns)
+			if runs < 0 || runs > maxFlakyTestRuns {
+				return mcp.NewToolResultError(fmt.Sprintf("runs must be between 0 and %d", maxFlakyTestRuns)), nil
+			}
+
+			span.SetAttributes(
+				attribute.String("org", org),
+				attribute.String("test_suite_slug", testSuiteSlug),
+				attribute.String("query", query),
+				attribute.String("field", field),
+				attribute.Int("runs", runs),
+			)
+
+			search := testSearch{query: strings.ToLower(query), field: field, byID: make(map[string]int), matches: make([]TestMatch, 0)}
+			var errs []string
+
+			flaky, err := listFlakyTests(ctx, flakyTests, org, testSuiteSlug)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("flaky tests: %s", err))
+			}
+			for _, test := range flaky {
+				search.add(TestMatch{
+					ID:       test.ID,
+					Name:     test.Name,
+					Scope:    test.Scope,
+					Location: test.Location,
+					FileName: test.FileName,
+					WebURL:   test.WebURL,
+				}, "flaky_tests", test.MostRecentInstanceAt)
+			}
+
+			if runs > 0 {
+				recent, err := listTestRunsUpTo(ctx, testRuns, org, testSuiteSlug, runs)
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("test runs: %s", err))
+				}
+
+				analyzed, failures, failureErrs := getFailedExecutions(ctx, testRuns, org, testSuiteSlug, recent)
+				errs = append(errs, failureErrs...)
+				for i, executions := range failures {
+					for _, execution := range executions {
+						at := execution.CreatedAt
+						if at == nil {
+							at = analyzed[i].CreatedAt
+						}
+						search.add(TestMatch{
+							ID:       execution.TestID,
+							Name:     execution.TestName,
+							Location: execution.Location,
+						}, "failed_executions", at)
+					}
+				}
+			}
+
+			result := TestSearchResult{
+				Tests:  applyClientSidePagination(search.matches, getClientSidePaginationParams(request)),
+				Errors: errs,
+			}
+
+			span.SetAttributes(attribute.Int("matches", result.Tests.Total))
+
+			r, err := json.Marshal(&result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal test search: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// testSearch collects the tests matching a lowercased query, merging those seen in several places.
+// Tests are indexed by position as matches grows.
+type testSearch struct {
+	query   string
+	field   string
+	byID    map[string]int
+	matches []TestMatch
+}
+
+func (s *testSearch) add(test TestMatch, source string, seen *buildkite.Timestamp) {
+	if i, ok := s.byID[test.ID]; ok {
+		match := &s.matches[i]
+		if !slices.Contains(match.Sources, source) {
+			match.Sources = append(match.Sources, source)
+		}
+		if seen != nil && (match.LastSeen == nil || seen.After(*match.LastSeen)) {
+			match.LastSeen = &seen.Time
+		}
+		return
+	}
+
+	if !s.matchesQuery(test) {
+		return
+	}
+
+	test.Sources = []string{source}
+	if seen != nil {
+		test.LastSeen = &seen.Time
+	}
+	s.byID[test.ID] = len(s.matches)
+	s.matches = append(s.matches, test)
+}
+
+func (s *testSearch) matchesQuery(test TestMatch) bool {
+	contains := func(values ...string) bool {
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), s.query) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch s.field {
+	case "name":
+		return contains(test.Name)
+	case "scope":
+		return contains(test.Scope)
+	case "location":
+		return contains(test.Location, test.FileName)
+	default:
+		return contains(test.Name, test.Scope, test.Location, test.FileName, test.Scope+" "+test.Name)
+	}
+}
+
+// listFlakyTests reads the pages of a suite's flaky tests
+func listFlakyTests(ctx context.Context, client FlakyTestsClient, org, testSuiteSlug string) ([]buildkite.FlakyTest, error) {
+	var all []buildkite.FlakyTest
+
+	options := &buildkite.FlakyTestsListOptions{
+		ListOptions: buildkite.ListOptions{PerPage: 100},
+	}
+	for range maxFlakyTestPages {
+		flakyTests, resp, err := client.List(ctx, org, testSuiteSlug, options)
+		if err != nil {
+			return all, newAPIError("list flaky tests", resp, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return all, newAPIError("list flaky tests", resp, nil)
+		}
+
+		all = append(all, flakyTests...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		options.Page = resp.NextPage
+	}
+
+	return all, nil
+}
@@ -2,10 +2,12 @@ package buildkite
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buildkite/go-buildkite/v4"
 	"github.com/stretchr/testify/require"
@@ -69,4 +71,98 @@ func TestGetTest(t *testing.T) {
 	// Verify test_id is required
 	testIDParam := params["test_id"].(map[string]interface{})
 	assert.Equal("string", testIDParam["type"])
-}
\ No newline at end of file
+}
+
+type MockFlakyTestsClient struct {
+	ListFunc func(ctx context.Context, org, slug string, opt *buildkite.FlakyTestsListOptions) ([]buildkite.FlakyTest, *buildkite.Response, error)
+}
+
+func (m *MockFlakyTestsClient) List(ctx context.Context, org, slug string, opt *buildkite.FlakyTestsListOptions) ([]buildkite.FlakyTest, *buildkite.Response, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, org, slug, opt)
+	}
+	return nil, nil, nil
+}
+
+var _ FlakyTestsClient = (*MockFlakyTestsClient)(nil)
+
+func TestSearchTests(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+
+	seen := &buildkite.Timestamp{Time: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)}
+	later := &buildkite.Timestamp{Time: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)}
+
+	flakyTests := &MockFlakyTestsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.FlakyTestsListOptions) ([]buildkite.FlakyTest, *buildkite.Response, error) {
+			return []buildkite.FlakyTest{
+					{ID: "test-1", Scope: "User", Name: "signs up", Location: "spec/models/user_spec.rb:10", FileName: "spec/models/user_spec.rb", MostRecentInstanceAt: seen},
+					{ID: "test-2", Scope: "Billing", Name: "renews", Location: "spec/models/billing_spec.rb:5"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	testRuns := &MockTestRunsClient{
+		ListFunc: func(ctx context.Context, org, slug string, opt *buildkite.TestRunsListOptions) ([]buildkite.TestRun, *buildkite.Response, error) {
+			return []buildkite.TestRun{
+					{ID: "run-1", State: "finished", Result: "failed", CreatedAt: later},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+		GetFailedExecutionsFunc: func(ctx context.Context, org, slug, runID string, opt *buildkite.FailedExecutionsOptions) ([]buildkite.FailedExecution, *buildkite.Response, error) {
+			return []buildkite.FailedExecution{
+					{TestID: "test-1", TestName: "User signs up", Location: "spec/models/user_spec.rb:10"},
+					{TestID: "test-3", TestName: "User logs in", Location: "spec/requests/session_spec.rb:3"},
+					{TestID: "test-4", TestName: "Admin bans", Location: "spec/models/admin_spec.rb:8"},
+				}, &buildkite.Response{
+					Response: &http.Response{
+						StatusCode: 200,
+					},
+				}, nil
+		},
+	}
+
+	tool, handler := SearchTests(ctx, flakyTests, testRuns)
+	assert.NotNil(tool)
+	assert.NotNil(handler)
+
+	request := createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"query":           "USER",
+	})
+	result, err := handler(ctx, request)
+	assert.NoError(err)
+
+	var search TestSearchResult
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &search))
+	assert.Empty(search.Errors)
+	assert.Equal(2, search.Tests.Total)
+
+	assert.Equal("test-1", search.Tests.Items[0].ID)
+	assert.Equal("User", search.Tests.Items[0].Scope)
+	assert.Equal([]string{"flaky_tests", "failed_executions"}, search.Tests.Items[0].Sources)
+	assert.Equal(later.Time, *search.Tests.Items[0].LastSeen)
+	assert.Equal("test-3", search.Tests.Items[1].ID)
+
+	request = createMCPRequest(t, map[string]any{
+		"org":             "org",
+		"test_suite_slug": "suite",
+		"query":           "spec/models",
+		"field":           "location",
+		"runs":            float64(0),
+	})
+	result, err = handler(ctx, request)
+	assert.NoError(err)
+	assert.NoError(json.Unmarshal([]byte(getTextResult(t, result).Text), &search))
+	assert.Equal(2, search.Tests.Total)
+	assert.Equal([]string{"flaky_tests"}, search.Tests.Items[1].Sources)
+}
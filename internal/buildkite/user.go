@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/buildkite/buildkite-mcp-server/internal/trace"
 	"github.com/buildkite/go-buildkite/v4"
@@ -28,11 +29,11 @@ func CurrentUser(ctx context.Context, client UserClient) (tool mcp.Tool, handler
 
 			user, resp, err := client.CurrentUser(ctx)
 			if err != nil {
-				return mcp.NewToolResultError(err.Error()), nil
+				return apiErrorResult("get current user", resp, err), nil
 			}
 
-			if resp.StatusCode != 200 {
-				return mcp.NewToolResultError("failed to get current user"), nil
+			if resp.StatusCode != http.StatusOK {
+				return apiErrorResult("get current user", resp, nil), nil
 			}
 
 			r, err := json.Marshal(&user)
@@ -12,6 +12,8 @@ type Globals struct {
 	Client  *buildkite.Client
 	Version string
 	Logger  zerolog.Logger
+	// SkipScopeCheck enables every tool without checking the API token has the scopes they need
+	SkipScopeCheck bool
 }
 
 func UserAgent(version string) string {
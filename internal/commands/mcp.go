@@ -25,40 +25,83 @@ func NewMCPServer(ctx context.Context, globals *Globals) *server.MCPServer {
 
 	log.Ctx(ctx).Info().Str("version", globals.Version).Msg("Starting Buildkite MCP server")
 
-	s.AddTools(BuildkiteTools(ctx, globals.Client)...)
+	tools := BuildkiteTools(ctx, globals.Client)
+	if !globals.SkipScopeCheck {
+		tools = FilterToolsByTokenScopes(ctx, globals.Client.AccessTokens, tools)
+	}
+
+	s.AddTools(tools...)
+
+	s.AddPrompts(BuildkitePrompts(ctx, globals.Client)...)
+
+	return s
+}
 
-	s.AddPrompt(mcp.NewPrompt("user_token_organization_prompt",
+func BuildkitePrompts(ctx context.Context, client *gobuildkite.Client) []server.ServerPrompt {
+	var prompts []server.ServerPrompt
+
+	addPrompt := func(prompt mcp.Prompt, handler server.PromptHandlerFunc) []server.ServerPrompt {
+		return append(prompts, server.ServerPrompt{Prompt: prompt, Handler: handler})
+	}
+
+	prompts = addPrompt(mcp.NewPrompt("user_token_organization_prompt",
 		mcp.WithPromptDescription("When asked for detail of a users pipelines start by looking up the user's token organization"),
 	), buildkite.HandleUserTokenOrganizationPrompt)
 
-	return s
+	// Diagnostic prompts which pre-fetch data from the API
+	prompts = addPrompt(buildkite.DebugFailedBuildPrompt(ctx, client.Builds, &buildkite.AnnotationsClientAdapter{Client: client}))
+	prompts = addPrompt(buildkite.InvestigateFlakyTestPrompt(ctx, client.Tests, client.TestRuns))
+	prompts = addPrompt(buildkite.SummarizePipelineHealthPrompt(ctx, client.Pipelines, client.Builds))
+	prompts = addPrompt(buildkite.ExplainQueueBacklogPrompt(ctx, client.Clusters, &buildkite.ClusterQueuesClientAdapter{Client: client}, client.Builds))
+
+	return prompts
 }
 
 func BuildkiteTools(ctx context.Context, client *gobuildkite.Client) []server.ServerTool {
 	// Create a client adapter so that we can use a mock or true client
 	clientAdapter := &buildkite.BuildkiteClientAdapter{Client: client}
+	annotationsAdapter := &buildkite.AnnotationsClientAdapter{Client: client}
+	clusterQueuesAdapter := &buildkite.ClusterQueuesClientAdapter{Client: client}
+	clusterTokensAdapter := &buildkite.ClusterTokensClientAdapter{Client: client}
 
 	var tools []server.ServerTool
 
 	addTool := func(tool mcp.Tool, handler server.ToolHandlerFunc) []server.ServerTool {
-		return append(tools, server.ServerTool{Tool: tool, Handler: handler})
+		return append(tools, server.ServerTool{Tool: buildkite.WithScopesDescription(tool), Handler: handler})
 	}
 
 	// Cluster tools
 	tools = addTool(buildkite.GetCluster(ctx, client.Clusters))
 	tools = addTool(buildkite.ListClusters(ctx, client.Clusters))
+	tools = addTool(buildkite.CreateCluster(ctx, client.Clusters))
+	tools = addTool(buildkite.UpdateCluster(ctx, client.Clusters))
+
+	// Cluster token tools
+	tools = addTool(buildkite.ListClusterTokens(ctx, clusterTokensAdapter))
+	tools = addTool(buildkite.CreateClusterToken(ctx, clusterTokensAdapter))
+	tools = addTool(buildkite.RevokeClusterToken(ctx, clusterTokensAdapter))
 
 	// Queue tools
-	tools = addTool(buildkite.GetClusterQueue(ctx, client.ClusterQueues))
-	tools = addTool(buildkite.ListClusterQueues(ctx, client.ClusterQueues))
+	tools = addTool(buildkite.GetClusterQueue(ctx, clusterQueuesAdapter))
+	tools = addTool(buildkite.ListClusterQueues(ctx, clusterQueuesAdapter))
+	tools = addTool(buildkite.CreateClusterQueue(ctx, clusterQueuesAdapter))
+	tools = addTool(buildkite.UpdateClusterQueue(ctx, clusterQueuesAdapter))
+	tools = addTool(buildkite.PauseQueueDispatch(ctx, clusterQueuesAdapter))
+	tools = addTool(buildkite.ResumeQueueDispatch(ctx, clusterQueuesAdapter))
 
 	// Pipeline tools
 	tools = addTool(buildkite.GetPipeline(ctx, client.Pipelines))
 	tools = addTool(buildkite.ListPipelines(ctx, client.Pipelines))
+	tools = addTool(buildkite.CreatePipeline(ctx, client.Pipelines))
+	tools = addTool(buildkite.UpdatePipeline(ctx, client.Pipelines))
+	tools = addTool(buildkite.GetPipelineGraph(ctx, client.Pipelines))
+	tools = addTool(buildkite.LintPipelineYAML(ctx))
 
 	// Build tools
 	tools = addTool(buildkite.ListBuilds(ctx, client.Builds))
 	tools = addTool(buildkite.GetBuild(ctx, client.Builds))
+	tools = addTool(buildkite.AnalyzeBuildTiming(ctx, client.Builds, client.Pipelines))
+	tools = addTool(buildkite.GetPipelineMetrics(ctx, client.Builds))
 	tools = addTool(buildkite.GetBuildTestEngineRuns(ctx, client.Builds))
 
 	// User tools
@@ -69,22 +112,43 @@ func BuildkiteTools(ctx context.Context, client *gobuildkite.Client) []server.Se
 	tools = addTool(buildkite.GetJobs(ctx, client.Builds))
 	tools = addTool(buildkite.GetJobLogs(ctx, client))
 
+	// Diagnostic tools
+	tools = addTool(buildkite.DiagnoseBuild(ctx, client.Builds, client.Jobs, annotationsAdapter, client.TestRuns))
+
+	// Agent tools
+	tools = addTool(buildkite.ListAgents(ctx, client.Agents))
+	tools = addTool(buildkite.GetAgent(ctx, client.Agents))
+	tools = addTool(buildkite.StopAgent(ctx, client.Agents))
+
 	// Artifacts tools
 	tools = addTool(buildkite.ListArtifacts(ctx, clientAdapter))
 	tools = addTool(buildkite.GetArtifact(ctx, clientAdapter))
+	tools = addTool(buildkite.ListArtifactArchive(ctx, clientAdapter))
+	tools = addTool(buildkite.ReadArtifactArchiveEntry(ctx, clientAdapter))
+	tools = addTool(buildkite.GetJUnitResults(ctx, clientAdapter))
+	tools = addTool(buildkite.GetCoverageSummary(ctx, clientAdapter))
 
 	// Annotation tools
-	tools = addTool(buildkite.ListAnnotations(ctx, client.Annotations))
+	tools = addTool(buildkite.ListAnnotations(ctx, annotationsAdapter))
+	tools = addTool(buildkite.CreateAnnotation(ctx, annotationsAdapter))
+	tools = addTool(buildkite.DeleteAnnotation(ctx, annotationsAdapter))
 
 	// Test Run tools
-	tools = addTool(buildkite.ListTestRuns(ctx, client.TestRuns))
+	tools = addTool(buildkite.ListTestRuns(ctx, client.TestRuns, client.Builds))
 	tools = addTool(buildkite.GetTestRun(ctx, client.TestRuns))
+	tools = addTool(buildkite.GetTestRunBuild(ctx, client.TestRuns, client.Builds))
 
 	// Test Execution tools
 	tools = addTool(buildkite.GetFailedTestExecutions(ctx, client.TestRuns))
+	tools = addTool(buildkite.FindFlakyTests(ctx, client.TestRuns))
 
 	// Test tools
 	tools = addTool(buildkite.GetTest(ctx, client.Tests))
+	tools = addTool(buildkite.SearchTests(ctx, client.FlakyTests, client.TestRuns))
+	tools = addTool(buildkite.GetTestHistory(ctx, client.Tests, client.TestRuns))
+
+	// Test Suite tools
+	tools = addTool(buildkite.ListTestSuites(ctx, client.TestSuites))
 
 	// Other tools
 	tools = addTool(buildkite.AccessToken(ctx, client.AccessTokens))
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// disabledTool is a tool left out because the API token is missing scopes it needs
+type disabledTool struct {
+	name          string
+	missingScopes []string
+}
+
+// FilterToolsByTokenScopes looks up the API token's scopes and leaves out the tools it can't use,
+// logging which were disabled. If the token can't be looked up every tool is kept, as their
+// requests will still fail with an error saying what's wrong.
+func FilterToolsByTokenScopes(ctx context.Context, client buildkite.AccessTokenClient, tools []server.ServerTool) []server.ServerTool {
+	token, resp, err := client.Get(ctx)
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Failed to check the API token's scopes, enabling all tools")
+		return tools
+	}
+
+	enabled, disabled := filterToolsByScopes(tools, token.Scopes)
+
+	for _, tool := range disabled {
+		log.Ctx(ctx).Warn().
+			Str("tool", tool.name).
+			Strs("missing_scopes", tool.missingScopes).
+			Msg("Disabled tool the API token doesn't have the scopes for")
+	}
+
+	log.Ctx(ctx).Info().
+		Strs("scopes", token.Scopes).
+		Int("enabled", len(enabled)).
+		Int("disabled", len(disabled)).
+		Msg("Checked API token scopes")
+
+	return enabled
+}
+
+func filterToolsByScopes(tools []server.ServerTool, granted []string) ([]server.ServerTool, []disabledTool) {
+	var (
+		enabled  []server.ServerTool
+		disabled []disabledTool
+	)
+	for _, tool := range tools {
+		missing := buildkite.MissingScopes(tool.Tool.Name, granted)
+		if len(missing) > 0 {
+			disabled = append(disabled, disabledTool{name: tool.Tool.Name, missingScopes: missing})
+			continue
+		}
+		enabled = append(enabled, tool)
+	}
+	return enabled, disabled
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/buildkite/buildkite-mcp-server/internal/buildkite"
+	gobuildkite "github.com/buildkite/go-buildkite/v4"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccessTokenClient struct {
+	GetFunc func(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error)
+}
+
+func (m *mockAccessTokenClient) Get(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	return gobuildkite.AccessToken{}, nil, nil
+}
+
+var _ buildkite.AccessTokenClient = (*mockAccessTokenClient)(nil)
+
+func toolNames(tools []server.ServerTool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	return names
+}
+
+func TestBuildkiteToolsHaveScopes(t *testing.T) {
+	for _, tool := range BuildkiteTools(context.Background(), &gobuildkite.Client{}) {
+		_, ok := buildkite.RequiredScopes(tool.Tool.Name)
+		require.True(t, ok, "no scopes are listed for %s", tool.Tool.Name)
+	}
+}
+
+func TestFilterToolsByTokenScopes(t *testing.T) {
+	assert := require.New(t)
+
+	ctx := context.Background()
+	client := &mockAccessTokenClient{
+		GetFunc: func(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error) {
+			return gobuildkite.AccessToken{
+					UUID:   "token-uuid",
+					Scopes: []string{buildkite.ScopeReadBuilds, buildkite.ScopeReadPipelines},
+				}, &gobuildkite.Response{
+					Response: &http.Response{
+						StatusCode: http.StatusOK,
+					},
+				}, nil
+		},
+	}
+
+	tools := FilterToolsByTokenScopes(ctx, client, BuildkiteTools(ctx, &gobuildkite.Client{}))
+	names := toolNames(tools)
+
+	assert.Contains(names, "get_build")
+	assert.Contains(names, "get_pipeline")
+	assert.Contains(names, "lint_pipeline_yaml")
+	assert.Contains(names, "access_token")
+	assert.NotContains(names, "get_job_logs")
+	assert.NotContains(names, "diagnose_build")
+	assert.NotContains(names, "create_pipeline")
+	assert.NotContains(names, "list_clusters")
+}
+
+func TestFilterToolsByTokenScopesLookupFailed(t *testing.T) {
+	ctx := context.Background()
+	all := BuildkiteTools(ctx, &gobuildkite.Client{})
+
+	tests := []struct {
+		name string
+		get  func(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error)
+	}{
+		{
+			name: "request error",
+			get: func(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error) {
+				return gobuildkite.AccessToken{}, nil, errors.New("connection refused")
+			},
+		},
+		{
+			name: "unexpected status",
+			get: func(ctx context.Context) (gobuildkite.AccessToken, *gobuildkite.Response, error) {
+				return gobuildkite.AccessToken{}, &gobuildkite.Response{
+					Response: &http.Response{
+						StatusCode: http.StatusServiceUnavailable,
+					},
+				}, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools := FilterToolsByTokenScopes(ctx, &mockAccessTokenClient{GetFunc: tt.get}, all)
+			require.Equal(t, toolNames(all), toolNames(tools))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -25,3 +26,38 @@ func EstimateTokens(text string) int {
 
 	return tokenCount
 }
+
+// TailWithinBudget returns the trailing lines of text whose estimated token count
+// fits within maxTokens, and whether anything was dropped. The end of a log is
+// usually where the failure is, so lines are kept from the bottom up. If the last
+// line alone is over the budget, the end of that line is kept instead.
+func TailWithinBudget(text string, maxTokens int) (string, bool) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	used := 0
+	start := len(lines)
+	for start > 0 {
+		cost := EstimateTokens(lines[start-1])
+		if used+cost > maxTokens {
+			break
+		}
+		used += cost
+		start--
+	}
+
+	if start == len(lines) {
+		return tailOfLine(lines[start-1], maxTokens), true
+	}
+
+	return strings.Join(lines[start:], "\n"), start > 0
+}
+
+// tailOfLine returns the longest end of line whose estimated token count fits within
+// maxTokens. Adding characters never lowers the estimate, so it can be searched for.
+func tailOfLine(line string, maxTokens int) string {
+	runes := []rune(line)
+	over := sort.Search(len(runes)+1, func(n int) bool {
+		return EstimateTokens(string(runes[len(runes)-n:])) > maxTokens
+	})
+	return strings.TrimSpace(string(runes[len(runes)-(over-1):]))
+}
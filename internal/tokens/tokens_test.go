@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -56,3 +57,54 @@ func TestEstimateTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestTailWithinBudget(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		maxTokens     int
+		expected      string
+		wantTruncated bool
+	}{
+		{
+			name:          "fits entirely",
+			input:         "one\ntwo\nthree\n",
+			maxTokens:     10,
+			expected:      "one\ntwo\nthree",
+			wantTruncated: false,
+		},
+		{
+			name:          "keeps trailing lines",
+			input:         "one\ntwo\nthree",
+			maxTokens:     3,
+			expected:      "two\nthree",
+			wantTruncated: true,
+		},
+		{
+			name:          "last line over budget",
+			input:         "ok\nError: " + strings.Repeat("abcd", 20) + " at main.go:10\n",
+			maxTokens:     4,
+			expected:      "at main.go:10",
+			wantTruncated: true,
+		},
+		{
+			name:          "zero budget",
+			input:         "one\ntwo",
+			maxTokens:     0,
+			expected:      "",
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, truncated := TailWithinBudget(tt.input, tt.maxTokens)
+			if result != tt.expected {
+				t.Errorf("TailWithinBudget(%q, %d) = %q, expected %q", tt.input, tt.maxTokens, result, tt.expected)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("TailWithinBudget(%q, %d) truncated = %v, expected %v", tt.input, tt.maxTokens, truncated, tt.wantTruncated)
+			}
+		})
+	}
+}